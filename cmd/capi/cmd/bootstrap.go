@@ -5,6 +5,9 @@
 package cmd
 
 import (
+	"fmt"
+	"io"
+
 	"github.com/spf13/cobra"
 
 	"github.com/siderolabs/capi-utils/pkg/capi"
@@ -25,6 +28,30 @@ var bootstrapCmd = &cobra.Command{
 	},
 }
 
+// bootstrapResult is the result of the bootstrap commands.
+type bootstrapResult struct {
+	CAPIVersion string              `json:"capiVersion"`
+	Providers   []installedProvider `json:"providers"`
+}
+
+type installedProvider struct {
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	Version   string `json:"version,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func (res *bootstrapResult) printTable(w io.Writer) error {
+	fmt.Fprintf(w, "CAPI version %s\n\n", res.CAPIVersion) //nolint:errcheck
+	fmt.Fprintln(w, "TYPE\tNAME\tVERSION\tNAMESPACE")      //nolint:errcheck
+
+	for _, provider := range res.Providers {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", provider.Type, provider.Name, provider.Version, provider.Namespace) //nolint:errcheck
+	}
+
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(bootstrapCmd)
 
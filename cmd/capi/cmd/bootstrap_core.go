@@ -6,6 +6,7 @@ package cmd
 
 import (
 	"context"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -29,7 +30,7 @@ var capiCoreCmd = &cobra.Command{
 
 		var err error
 
-		manager, err = capi.NewManager(ctx, capi.Options{
+		manager, err = newManager(ctx, capi.Options{
 			ClusterctlConfigPath:    options.ClusterctlConfigPath,
 			CoreProvider:            options.CoreProvider,
 			BootstrapProviders:      options.BootstrapProviders,
@@ -40,10 +41,40 @@ var capiCoreCmd = &cobra.Command{
 			return err
 		}
 
-		return manager.Install(ctx)
+		if err = manager.Install(ctx); err != nil {
+			return err
+		}
+
+		res := &bootstrapResult{
+			CAPIVersion: manager.Version(),
+			Providers: []installedProvider{
+				clusterctlProvider("core", options.CoreProvider),
+			},
+		}
+
+		for _, name := range options.BootstrapProviders {
+			res.Providers = append(res.Providers, clusterctlProvider("bootstrap", name))
+		}
+
+		for _, name := range options.ControlPlaneProviders {
+			res.Providers = append(res.Providers, clusterctlProvider("control-plane", name))
+		}
+
+		return printResult(res)
 	},
 }
 
+// clusterctlProvider parses the clusterctl provider name[:version] string.
+func clusterctlProvider(providerType, provider string) installedProvider {
+	name, version, _ := strings.Cut(provider, ":")
+
+	return installedProvider{
+		Type:    providerType,
+		Name:    name,
+		Version: version,
+	}
+}
+
 func init() {
 	bootstrapCmd.AddCommand(capiCoreCmd)
 }
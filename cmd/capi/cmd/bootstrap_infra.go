@@ -39,6 +39,7 @@ var capiInfraCmd = &cobra.Command{
 				name,
 				infrastructure.WithProviderNS(targetNS),
 				infrastructure.WithWatchingNS(watchingNS),
+				infrastructure.WithLogger(logger),
 			)
 			if err != nil {
 				return err
@@ -55,7 +56,7 @@ var capiInfraCmd = &cobra.Command{
 
 		var err error
 
-		manager, err = capi.NewManager(ctx, capi.Options{
+		manager, err = newManager(ctx, capi.Options{
 			ClusterctlConfigPath:    options.ClusterctlConfigPath,
 			CoreProvider:            "",
 			BootstrapProviders:      []string{},
@@ -66,7 +67,25 @@ var capiInfraCmd = &cobra.Command{
 			return err
 		}
 
-		return manager.Install(ctx)
+		if err = manager.Install(ctx); err != nil {
+			return err
+		}
+
+		res := &bootstrapResult{
+			CAPIVersion: manager.Version(),
+			Providers:   make([]installedProvider, 0, len(providers)),
+		}
+
+		for _, provider := range providers {
+			res.Providers = append(res.Providers, installedProvider{
+				Type:      "infrastructure",
+				Name:      provider.Name(),
+				Version:   provider.Version(),
+				Namespace: provider.Namespace(),
+			})
+		}
+
+		return printResult(res)
 	},
 }
 
@@ -24,7 +24,7 @@ var clusterCmd = &cobra.Command{
 
 		var err error
 
-		manager, err = capi.NewManager(ctx, capi.Options{})
+		manager, err = newManager(ctx, capi.Options{})
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,135 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/siderolabs/capi-utils/pkg/capi"
+)
+
+var clusterAutoscaleCmdFlags struct {
+	machineDeployment string
+	placement         string
+	image             string
+	minSize           int
+	maxSize           int
+	disable           bool
+	deploy            bool
+}
+
+var autoscalerPlacements = map[string]capi.AutoscalerPlacement{
+	"management": capi.AutoscalerInManagementCluster,
+	"workload":   capi.AutoscalerInWorkloadCluster,
+}
+
+var clusterAutoscaleCmd = &cobra.Command{
+	Use:   "autoscale",
+	Short: "Configure cluster autoscaler for a CAPI cluster.",
+	Long:  ``,
+	Example: `
+	## Enable autoscaling for the machine deployment and deploy the autoscaler into the management cluster
+	capi cluster autoscale --machine-deployment talos-default-workers --min 1 --max 5 --deploy-autoscaler
+
+	## Disable autoscaling for the machine deployment
+	capi cluster autoscale --machine-deployment talos-default-workers --disable
+	`,
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+
+		placement, ok := autoscalerPlacements[clusterAutoscaleCmdFlags.placement]
+		if !ok {
+			return usageError("autoscaler placement can be either 'management' or 'workload', got: %q", clusterAutoscaleCmdFlags.placement)
+		}
+
+		if clusterAutoscaleCmdFlags.machineDeployment == "" {
+			return usageError("machine deployment name is required")
+		}
+
+		cluster, err := manager.NewCluster(ctx, clusterCmdFlags.clusterName, clusterCmdFlags.clusterNamespace)
+		if err != nil {
+			return err
+		}
+
+		res := &clusterAutoscaleResult{
+			clusterRef:        clusterRef{Name: cluster.Name(), Namespace: cluster.Namespace()},
+			MachineDeployment: clusterAutoscaleCmdFlags.machineDeployment,
+		}
+
+		if clusterAutoscaleCmdFlags.disable {
+			if err = cluster.DisableAutoscaling(ctx, clusterAutoscaleCmdFlags.machineDeployment); err != nil {
+				return err
+			}
+
+			return printResult(res)
+		}
+
+		if err = cluster.SetAutoscaling(ctx, clusterAutoscaleCmdFlags.machineDeployment, clusterAutoscaleCmdFlags.minSize, clusterAutoscaleCmdFlags.maxSize); err != nil {
+			return err
+		}
+
+		res.Enabled = true
+		res.MinSize = clusterAutoscaleCmdFlags.minSize
+		res.MaxSize = clusterAutoscaleCmdFlags.maxSize
+
+		if clusterAutoscaleCmdFlags.deploy {
+			if err = cluster.DeployAutoscaler(ctx,
+				capi.WithAutoscalerPlacement(placement),
+				capi.WithAutoscalerImage(clusterAutoscaleCmdFlags.image),
+			); err != nil {
+				return err
+			}
+
+			res.AutoscalerPlacement = clusterAutoscaleCmdFlags.placement
+		}
+
+		return printResult(res)
+	},
+}
+
+// clusterAutoscaleResult is the result of the autoscale command.
+type clusterAutoscaleResult struct {
+	clusterRef
+
+	MachineDeployment string `json:"machineDeployment"`
+	// AutoscalerPlacement is set if the autoscaler was deployed.
+	AutoscalerPlacement string `json:"autoscalerPlacement,omitempty"`
+	MinSize             int    `json:"minSize,omitempty"`
+	MaxSize             int    `json:"maxSize,omitempty"`
+	Enabled             bool   `json:"enabled"`
+}
+
+func (res *clusterAutoscaleResult) printTable(w io.Writer) error {
+	if !res.Enabled {
+		_, err := fmt.Fprintf(w, "autoscaling of the machine deployment %s is disabled\n", res.MachineDeployment)
+
+		return err
+	}
+
+	fmt.Fprintf(w, "autoscaling of the machine deployment %s is enabled: %d-%d replicas\n", res.MachineDeployment, res.MinSize, res.MaxSize) //nolint:errcheck
+
+	if res.AutoscalerPlacement != "" {
+		fmt.Fprintf(w, "cluster autoscaler is deployed to the %s cluster\n", res.AutoscalerPlacement) //nolint:errcheck
+	}
+
+	return nil
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterAutoscaleCmd)
+
+	clusterAutoscaleCmd.Flags().StringVar(&clusterAutoscaleCmdFlags.machineDeployment, "machine-deployment", "", "Name of the machine deployment to autoscale")
+	clusterAutoscaleCmd.Flags().IntVar(&clusterAutoscaleCmdFlags.minSize, "min", 1, "Minimum number of machine deployment replicas")
+	clusterAutoscaleCmd.Flags().IntVar(&clusterAutoscaleCmdFlags.maxSize, "max", 1, "Maximum number of machine deployment replicas")
+	clusterAutoscaleCmd.Flags().BoolVar(&clusterAutoscaleCmdFlags.disable, "disable", false, "Disable autoscaling for the machine deployment")
+	clusterAutoscaleCmd.Flags().BoolVar(&clusterAutoscaleCmdFlags.deploy, "deploy-autoscaler", false, "Deploy the cluster autoscaler")
+	clusterAutoscaleCmd.Flags().StringVar(&clusterAutoscaleCmdFlags.placement, "autoscaler-placement", "management",
+		"Cluster to deploy the autoscaler to; valid values are 'management' or 'workload'")
+	clusterAutoscaleCmd.Flags().StringVar(&clusterAutoscaleCmdFlags.image, "autoscaler-image", capi.DefaultAutoscalerImage, "Cluster autoscaler image")
+}
@@ -6,6 +6,11 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -15,7 +20,18 @@ import (
 )
 
 var clusterCreateCmdFlags struct {
-	templatePath string
+	templatePath              string
+	specPath                  string
+	checksum                  string
+	patchesDir                string
+	cacheDir                  string
+	labels                    map[string]string
+	annotations               map[string]string
+	controlPlaneConfigPatches []string
+	workerConfigPatches       []string
+	ttl                       time.Duration
+	checkVars                 bool
+	cache                     bool
 }
 
 var deployOptions = capi.DefaultDeployOptions()
@@ -45,26 +61,153 @@ var clusterCreateCmd = &cobra.Command{
 			opts = append(opts, capi.WithTemplateFile(clusterCreateCmdFlags.templatePath))
 		}
 
-		cluster, err := manager.DeployCluster(ctx, clusterCmdFlags.clusterName, opts...)
+		if clusterCreateCmdFlags.checksum != "" {
+			opts = append(opts, capi.WithTemplateChecksum(clusterCreateCmdFlags.checksum))
+		}
+
+		if clusterCreateCmdFlags.cache {
+			opts = append(opts, capi.WithTemplateCache(clusterCreateCmdFlags.cacheDir))
+		}
+
+		if len(clusterCreateCmdFlags.labels) > 0 {
+			opts = append(opts, capi.WithLabels(clusterCreateCmdFlags.labels))
+		}
+
+		if len(clusterCreateCmdFlags.annotations) > 0 {
+			opts = append(opts, capi.WithAnnotations(clusterCreateCmdFlags.annotations))
+		}
+
+		if clusterCreateCmdFlags.ttl > 0 {
+			opts = append(opts, capi.WithTTL(clusterCreateCmdFlags.ttl))
+		}
+
+		if len(clusterCreateCmdFlags.controlPlaneConfigPatches) > 0 {
+			opts = append(opts, capi.WithControlPlaneConfigPatches(clusterCreateCmdFlags.controlPlaneConfigPatches...))
+		}
+
+		if len(clusterCreateCmdFlags.workerConfigPatches) > 0 {
+			opts = append(opts, capi.WithWorkerConfigPatches(clusterCreateCmdFlags.workerConfigPatches...))
+		}
+
+		if clusterCreateCmdFlags.patchesDir != "" {
+			patches, err := capi.LoadPatches(clusterCreateCmdFlags.patchesDir)
+			if err != nil {
+				return err
+			}
+
+			opts = append(opts, capi.WithPatches(patches...))
+		}
+
+		clusterName := clusterCmdFlags.clusterName
+
+		if clusterCreateCmdFlags.specPath != "" {
+			spec, err := loadClusterSpec(clusterCreateCmdFlags.specPath)
+			if err != nil {
+				return err
+			}
+
+			if spec.Name != "" {
+				clusterName = spec.Name
+			}
+
+			opts = append(opts, capi.WithClusterSpec(spec))
+		}
+
+		if clusterCreateCmdFlags.checkVars {
+			vars, err := manager.CheckTemplateVariables(ctx, clusterName, opts...)
+			if err != nil {
+				return err
+			}
+
+			return printResult(&clusterCreateResult{
+				clusterRef: clusterRef{Name: clusterName, Namespace: clusterCmdFlags.clusterNamespace},
+				Variables:  vars,
+			})
+		}
+
+		cluster, err := manager.DeployCluster(ctx, clusterName, opts...)
 		if err != nil {
 			return err
 		}
 
-		return cluster.Health(ctx)
+		if err = cluster.Health(ctx); err != nil {
+			return err
+		}
+
+		return printResult(&clusterCreateResult{
+			clusterRef: clusterRef{Name: cluster.Name(), Namespace: cluster.Namespace()},
+			Deployed:   true,
+		})
 	},
 }
 
+// clusterCreateResult is the result of the create command.
+type clusterCreateResult struct {
+	clusterRef
+
+	// Variables are set only with --check-vars.
+	Variables []string `json:"variables,omitempty"`
+	Deployed  bool     `json:"deployed"`
+}
+
+func (res *clusterCreateResult) printTable(w io.Writer) error {
+	var err error
+
+	if res.Deployed {
+		_, err = fmt.Fprintf(w, "cluster %s is deployed and healthy\n", res.clusterRef)
+	} else {
+		_, err = fmt.Fprintf(w, "all cluster template variables are set: %s\n", strings.Join(res.Variables, ", "))
+	}
+
+	return err
+}
+
+func loadClusterSpec(path string) (*capi.ClusterSpec, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster spec: %w", err)
+	}
+
+	return capi.LoadClusterSpec(data)
+}
+
 func init() {
 	clusterCmd.AddCommand(clusterCreateCmd)
 
-	clusterCreateCmd.Flags().StringVarP(&clusterCreateCmdFlags.templatePath, "from", "f",
-		"https://github.com/siderolabs/cluster-api-templates/blob/main/aws/standard/standard.yaml", "Custom path for the cluster template")
+	clusterCreateCmd.Flags().StringVar(&clusterCreateCmdFlags.templatePath, "from",
+		"https://github.com/siderolabs/cluster-api-templates/blob/main/aws/standard/standard.yaml", "Custom path for the cluster template: local path, URL, oci://registry/repository:tag[//file] or git+https://host/repository.git//path?ref=ref")
+	clusterCreateCmd.Flags().StringVar(&clusterCreateCmdFlags.checksum, "template-checksum", "", "Expected sha256 checksum of the cluster template")
+	clusterCreateCmd.Flags().BoolVar(&clusterCreateCmdFlags.cache, "template-cache", false, "Cache fetched cluster templates, pinned templates are loaded from the cache offline")
+	clusterCreateCmd.Flags().StringVar(&clusterCreateCmdFlags.cacheDir, "template-cache-dir", "", "Cluster template cache directory, defaults to the user cache directory")
+	clusterCreateCmd.Flags().StringVarP(&clusterCreateCmdFlags.specPath, "file", "f", "",
+		"Path to the cluster spec file ('-' for stdin), values from the spec override the flags")
+	clusterCreateCmd.Flags().StringVar(&clusterCreateCmdFlags.patchesDir, "patches", "",
+		"Directory with patches applied to the cluster objects: kustomization.yaml with patches or JSON merge patch files")
+	clusterCreateCmd.Flags().StringToStringVar(&clusterCreateCmdFlags.labels, "labels", nil, "Labels added to all cluster objects")
+	clusterCreateCmd.Flags().StringToStringVar(&clusterCreateCmdFlags.annotations, "annotations", nil, "Annotations added to all cluster objects")
+	clusterCreateCmd.Flags().DurationVar(&clusterCreateCmdFlags.ttl, "ttl", 0, "Cluster time to live, expired clusters are destroyed by the gc command")
+	clusterCreateCmd.Flags().StringArrayVar(&clusterCreateCmdFlags.controlPlaneConfigPatches, "config-patch-control-plane", nil,
+		"Talos machine config patch for the control plane nodes, use @file to read the patch from the file")
+	clusterCreateCmd.Flags().StringArrayVar(&clusterCreateCmdFlags.workerConfigPatches, "config-patch-worker", nil,
+		"Talos machine config patch for the worker nodes, use @file to read the patch from the file")
+	clusterCreateCmd.Flags().BoolVar(&clusterCreateCmdFlags.checkVars, "check-vars", false, "Only check that all cluster template variables are set, do not deploy the cluster")
 	clusterCreateCmd.Flags().Int64Var(&deployOptions.ControlPlaneNodes, "control-plane-nodes", deployOptions.ControlPlaneNodes, "Number of control plane nodes to deploy")
 	clusterCreateCmd.Flags().Int64Var(&deployOptions.WorkerNodes, "worker-nodes", deployOptions.WorkerNodes, "Number of worker nodes to deploy")
 	clusterCreateCmd.Flags().StringVarP(&deployOptions.Provider, "provider", "p", deployOptions.Provider, "Infrastructure provider to use for the deployment")
 	clusterCreateCmd.Flags().StringVar(&deployOptions.ProviderVersion, "provider-version", deployOptions.ProviderVersion, "Provider version to use")
 	clusterCreateCmd.Flags().StringVar(&deployOptions.KubernetesVersion, "kubernetes-version", deployOptions.KubernetesVersion, "Kubernetes version to use")
 	clusterCreateCmd.Flags().StringVar(&deployOptions.TalosVersion, "talos-version", deployOptions.TalosVersion, "Talos version to use")
+	clusterCreateCmd.Flags().StringVar(&deployOptions.ClusterClass, "cluster-class", deployOptions.ClusterClass, "ClusterClass to create the cluster from, overrides the cluster template")
 	// AWS provider flags
 	clusterCreateCmd.Flags().StringVar(&awsDeployOptions.CloudProviderVersion, "aws-cloud-provider-version", awsDeployOptions.CloudProviderVersion, "AWS cloud provider version")
 
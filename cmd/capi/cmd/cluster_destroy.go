@@ -0,0 +1,100 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/siderolabs/capi-utils/pkg/capi"
+)
+
+var clusterDestroyCmdFlags struct {
+	selector string
+	all      bool
+}
+
+var clusterDestroyCmd = &cobra.Command{
+	Use:   "destroy",
+	Short: "Destroy CAPI clusters.",
+	Long: `Destroys the cluster by name or all clusters in the namespace matching the selector.
+By default only the clusters created by capi-utils can be destroyed.`,
+	Example: `
+	capi cluster destroy --name test
+	capi cluster destroy --selector ci-job=1234
+	`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx := context.Background()
+
+		selector, err := clusterSelector(clusterDestroyCmdFlags.selector, clusterDestroyCmdFlags.all)
+		if err != nil {
+			return err
+		}
+
+		if clusterDestroyCmdFlags.selector == "" || cmd.Flags().Changed("name") {
+			if err = manager.DestroyCluster(ctx, clusterCmdFlags.clusterName, clusterCmdFlags.clusterNamespace, capi.WithDestroySelector(selector)); err != nil {
+				return err
+			}
+
+			return printResult(&clusterDestroyResult{
+				Namespace: clusterCmdFlags.clusterNamespace,
+				Destroyed: []string{clusterCmdFlags.clusterName},
+			})
+		}
+
+		deleted, err := manager.DestroyClusters(ctx, clusterCmdFlags.clusterNamespace, selector)
+
+		// the clusters destroyed before the failure are reported as well
+		if printErr := printResult(&clusterDestroyResult{
+			Namespace: clusterCmdFlags.clusterNamespace,
+			Destroyed: deleted,
+		}); printErr != nil {
+			return printErr
+		}
+
+		return err
+	},
+}
+
+// clusterDestroyResult is the result of the destroy command.
+type clusterDestroyResult struct {
+	Namespace string   `json:"namespace"`
+	Destroyed []string `json:"destroyed"`
+}
+
+func (res *clusterDestroyResult) printTable(w io.Writer) error {
+	for _, name := range res.Destroyed {
+		fmt.Fprintf(w, "destroyed cluster %s\n", name) //nolint:errcheck
+	}
+
+	return nil
+}
+
+// clusterSelector parses the label selector, limiting it to the managed clusters unless all is set.
+func clusterSelector(selector string, all bool) (labels.Selector, error) {
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return nil, &capi.ValidationError{Err: err}
+	}
+
+	if all {
+		return parsed, nil
+	}
+
+	requirements, _ := capi.ManagedSelector(nil).Requirements()
+
+	return parsed.Add(requirements...), nil
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterDestroyCmd)
+
+	clusterDestroyCmd.Flags().StringVarP(&clusterDestroyCmdFlags.selector, "selector", "l", "", "Destroy all clusters in the namespace matching the label selector")
+	clusterDestroyCmd.Flags().BoolVar(&clusterDestroyCmdFlags.all, "all", false, "Allow destroying the clusters not created by capi-utils")
+}
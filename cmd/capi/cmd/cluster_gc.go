@@ -0,0 +1,106 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/siderolabs/capi-utils/pkg/capi"
+)
+
+var clusterGCCmdFlags struct {
+	concurrency   int
+	allNamespaces bool
+	dryRun        bool
+}
+
+var clusterGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Destroy expired CAPI clusters.",
+	Long:  `Destroys the clusters created by capi-utils with the expiry deadline in the past, see --ttl of the create command.`,
+	Example: `
+	capi cluster gc --all-namespaces --concurrency 8
+	`,
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+
+		opts := []capi.GCOption{
+			capi.WithGCConcurrency(clusterGCCmdFlags.concurrency),
+			capi.WithGCDryRun(clusterGCCmdFlags.dryRun),
+		}
+
+		if !clusterGCCmdFlags.allNamespaces {
+			opts = append(opts, capi.WithGCNamespace(clusterCmdFlags.clusterNamespace))
+		}
+
+		report, err := manager.GarbageCollect(ctx, time.Now(), opts...)
+		if err != nil {
+			return err
+		}
+
+		res := &clusterGCResult{
+			Failed:    make(map[string]string, len(report.Failed)),
+			Expired:   report.Expired,
+			Destroyed: report.Removed,
+			DryRun:    clusterGCCmdFlags.dryRun,
+		}
+
+		for name, failure := range report.Failed {
+			res.Failed[name] = failure.Error()
+		}
+
+		if err = printResult(res); err != nil {
+			return err
+		}
+
+		if len(report.Failed) > 0 {
+			return fmt.Errorf("failed to collect %d clusters", len(report.Failed))
+		}
+
+		return nil
+	},
+}
+
+// clusterGCResult is the result of the gc command.
+type clusterGCResult struct {
+	// Failed maps the cluster names to the failure messages.
+	Failed    map[string]string `json:"failed,omitempty"`
+	Expired   []string          `json:"expired"`
+	Destroyed []string          `json:"destroyed"`
+	DryRun    bool              `json:"dryRun"`
+}
+
+func (res *clusterGCResult) printTable(w io.Writer) error {
+	if res.DryRun {
+		for _, name := range res.Expired {
+			fmt.Fprintf(w, "expired cluster %s\n", name) //nolint:errcheck
+		}
+	}
+
+	for _, name := range res.Destroyed {
+		fmt.Fprintf(w, "destroyed cluster %s\n", name) //nolint:errcheck
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(res.Failed)) {
+		fmt.Fprintf(w, "failed to destroy cluster %s: %s\n", name, res.Failed[name]) //nolint:errcheck
+	}
+
+	return nil
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterGCCmd)
+
+	clusterGCCmd.Flags().IntVar(&clusterGCCmdFlags.concurrency, "concurrency", 4, "Number of clusters destroyed in parallel")
+	clusterGCCmd.Flags().BoolVarP(&clusterGCCmdFlags.allNamespaces, "all-namespaces", "A", false, "Collect the clusters in all namespaces")
+	clusterGCCmd.Flags().BoolVar(&clusterGCCmdFlags.dryRun, "dry-run", false, "Only list the expired clusters")
+}
@@ -0,0 +1,101 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+var clusterListCmdFlags struct {
+	selector      string
+	allNamespaces bool
+	all           bool
+}
+
+var clusterListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List CAPI clusters.",
+	Long:  `By default only the clusters created by capi-utils are listed.`,
+	Example: `
+	capi cluster list --selector team=platform
+	capi cluster list --all --all-namespaces
+	`,
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+
+		selector, err := clusterSelector(clusterListCmdFlags.selector, clusterListCmdFlags.all)
+		if err != nil {
+			return err
+		}
+
+		namespace := clusterCmdFlags.clusterNamespace
+		if clusterListCmdFlags.allNamespaces {
+			namespace = ""
+		}
+
+		clusters, err := manager.ListClusters(ctx, namespace, selector)
+		if err != nil {
+			return err
+		}
+
+		res := &clusterListResult{
+			Clusters: make([]listedCluster, 0, len(clusters.Items)),
+		}
+
+		for _, cluster := range clusters.Items {
+			phase, _, _ := unstructured.NestedString(cluster.Object, "status", "phase") //nolint:errcheck
+
+			res.Clusters = append(res.Clusters, listedCluster{
+				CreatedAt:  cluster.GetCreationTimestamp().Time,
+				clusterRef: clusterRef{Name: cluster.GetName(), Namespace: cluster.GetNamespace()},
+				Phase:      phase,
+			})
+		}
+
+		return printResult(res)
+	},
+}
+
+// clusterListResult is the result of the list command.
+type clusterListResult struct {
+	Clusters []listedCluster `json:"clusters"`
+}
+
+type listedCluster struct {
+	CreatedAt time.Time `json:"createdAt"`
+	clusterRef
+
+	Phase string `json:"phase"`
+}
+
+func (res *clusterListResult) printTable(w io.Writer) error {
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tPHASE\tAGE") //nolint:errcheck
+
+	for _, cluster := range res.Clusters {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", //nolint:errcheck
+			cluster.Namespace,
+			cluster.Name,
+			cluster.Phase,
+			duration.HumanDuration(time.Since(cluster.CreatedAt)),
+		)
+	}
+
+	return nil
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterListCmd)
+
+	clusterListCmd.Flags().StringVarP(&clusterListCmdFlags.selector, "selector", "l", "", "Label selector to filter the clusters")
+	clusterListCmd.Flags().BoolVarP(&clusterListCmdFlags.allNamespaces, "all-namespaces", "A", false, "List the clusters in all namespaces")
+	clusterListCmd.Flags().BoolVar(&clusterListCmdFlags.all, "all", false, "Include the clusters not created by capi-utils")
+}
@@ -0,0 +1,58 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+var clusterPauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pause reconciliation of a CAPI cluster.",
+	Long:  ``,
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+
+		cluster, err := manager.NewCluster(ctx, clusterCmdFlags.clusterName, clusterCmdFlags.clusterNamespace)
+		if err != nil {
+			return err
+		}
+
+		if err = cluster.Pause(ctx); err != nil {
+			return err
+		}
+
+		return printResult(&clusterPauseResult{
+			clusterRef: clusterRef{Name: cluster.Name(), Namespace: cluster.Namespace()},
+			Paused:     true,
+		})
+	},
+}
+
+// clusterPauseResult is the result of the pause and resume commands.
+type clusterPauseResult struct {
+	clusterRef
+
+	Paused bool `json:"paused"`
+}
+
+func (res *clusterPauseResult) printTable(w io.Writer) error {
+	state := "resumed"
+	if res.Paused {
+		state = "paused"
+	}
+
+	_, err := fmt.Fprintf(w, "cluster %s reconciliation is %s\n", res.clusterRef, state)
+
+	return err
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterPauseCmd)
+}
@@ -0,0 +1,38 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+)
+
+var clusterResumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume reconciliation of a paused CAPI cluster.",
+	Long:  ``,
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+
+		cluster, err := manager.NewCluster(ctx, clusterCmdFlags.clusterName, clusterCmdFlags.clusterNamespace)
+		if err != nil {
+			return err
+		}
+
+		if err = cluster.Resume(ctx); err != nil {
+			return err
+		}
+
+		return printResult(&clusterPauseResult{
+			clusterRef: clusterRef{Name: cluster.Name(), Namespace: cluster.Namespace()},
+			Paused:     false,
+		})
+	},
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterResumeCmd)
+}
@@ -0,0 +1,79 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var clusterRolloutRestartCmdFlags struct {
+	group string
+}
+
+var clusterRolloutRestartCmd = &cobra.Command{
+	Use:   "restart",
+	Short: "Replace all machines of a CAPI cluster nodes group.",
+	Long:  ``,
+	Example: `
+	capi cluster rollout restart --nodes workers
+	`,
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+
+		group, ok := groups[clusterRolloutRestartCmdFlags.group]
+		if !ok {
+			return usageError("nodes can be either 'control-planes', 'workers' or 'machine-pools', got: %q", clusterRolloutRestartCmdFlags.group)
+		}
+
+		cluster, err := manager.NewCluster(ctx, clusterCmdFlags.clusterName, clusterCmdFlags.clusterNamespace)
+		if err != nil {
+			return err
+		}
+
+		result, err := cluster.Rollout(ctx, group)
+		if err != nil {
+			return err
+		}
+
+		return printResult(&clusterRolloutResult{
+			clusterRef: clusterRef{Name: cluster.Name(), Namespace: cluster.Namespace()},
+			Nodes:      clusterRolloutRestartCmdFlags.group,
+			Before:     result.Before,
+			After:      result.After,
+		})
+	},
+}
+
+// clusterRolloutResult is the result of the rollout restart command.
+type clusterRolloutResult struct {
+	clusterRef
+
+	Nodes  string   `json:"nodes"`
+	Before []string `json:"before"`
+	After  []string `json:"after"`
+}
+
+func (res *clusterRolloutResult) printTable(w io.Writer) error {
+	fmt.Fprintf(w, "machines before rollout: %s\n", strings.Join(res.Before, ", ")) //nolint:errcheck
+	fmt.Fprintf(w, "machines after rollout: %s\n", strings.Join(res.After, ", "))   //nolint:errcheck
+
+	return nil
+}
+
+func init() {
+	clusterRolloutCmd.AddCommand(clusterRolloutRestartCmd)
+
+	clusterRolloutRestartCmd.Flags().StringVarP(
+		&clusterRolloutRestartCmdFlags.group,
+		"nodes", "",
+		"workers",
+		"Nodes to replace; valid values are 'control-planes', 'workers' or 'machine-pools'",
+	)
+}
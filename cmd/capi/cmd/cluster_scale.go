@@ -7,6 +7,9 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
+	"maps"
+	"slices"
 
 	"github.com/spf13/cobra"
 
@@ -14,29 +17,69 @@ import (
 )
 
 var clusterScaleCmdFlags struct {
-	group    string
-	replicas int
+	machineDeploymentReplicas map[string]int
+	group                     string
+	machineDeployment         string
+	machinePool               string
+	selector                  string
+	deleteMachines            []string
+	replicas                  int
+	allMachineDeployments     bool
+	force                     bool
 }
 
 var groups = map[string]capi.NodeGroup{
 	"control-planes": capi.ControlPlaneNodes,
 	"workers":        capi.WorkerNodes,
+	"machine-pools":  capi.MachinePoolNodes,
 }
 
 var clusterScaleCmd = &cobra.Command{
 	Use:   "scale",
 	Short: "Scale a CAPI cluster.",
 	Long:  ``,
-	RunE: func(*cobra.Command, []string) error {
+	RunE: func(cmd *cobra.Command, _ []string) error {
 		ctx := context.Background()
 
+		if err := inferScaleGroup(cmd.Flags().Changed("nodes")); err != nil {
+			return err
+		}
+
 		group, ok := groups[clusterScaleCmdFlags.group]
 		if !ok {
-			return fmt.Errorf("nodes can be either 'control-planes' or 'workers', got: %q", clusterScaleCmdFlags.group)
+			return usageError("nodes can be either 'control-planes', 'workers' or 'machine-pools', got: %q", clusterScaleCmdFlags.group)
+		}
+
+		if clusterScaleCmdFlags.replicas < 0 && len(clusterScaleCmdFlags.machineDeploymentReplicas) == 0 {
+			return usageError("number of replicas is required")
+		}
+
+		scaleOptions := []capi.ScaleOption{
+			capi.DeleteMachines(clusterScaleCmdFlags.deleteMachines...),
+		}
+
+		if clusterScaleCmdFlags.machineDeployment != "" {
+			scaleOptions = append(scaleOptions, capi.MachineDeploymentName(clusterScaleCmdFlags.machineDeployment))
+		}
+
+		if clusterScaleCmdFlags.machinePool != "" {
+			scaleOptions = append(scaleOptions, capi.MachinePoolName(clusterScaleCmdFlags.machinePool))
+		}
+
+		if clusterScaleCmdFlags.selector != "" {
+			scaleOptions = append(scaleOptions, capi.MachineDeploymentSelector(clusterScaleCmdFlags.selector))
+		}
+
+		if clusterScaleCmdFlags.allMachineDeployments {
+			scaleOptions = append(scaleOptions, capi.AllMachineDeployments())
 		}
 
-		if clusterScaleCmdFlags.replicas < 0 {
-			return fmt.Errorf("number of replicas is required")
+		if clusterScaleCmdFlags.force {
+			scaleOptions = append(scaleOptions, capi.ForceScale())
+		}
+
+		if len(clusterScaleCmdFlags.machineDeploymentReplicas) > 0 {
+			scaleOptions = append(scaleOptions, capi.MachineDeploymentReplicas(clusterScaleCmdFlags.machineDeploymentReplicas))
 		}
 
 		cluster, err := manager.NewCluster(ctx, clusterCmdFlags.clusterName, clusterCmdFlags.clusterNamespace)
@@ -44,10 +87,76 @@ var clusterScaleCmd = &cobra.Command{
 			return err
 		}
 
-		return cluster.Scale(ctx, clusterScaleCmdFlags.replicas, group)
+		if err = cluster.Scale(ctx, clusterScaleCmdFlags.replicas, group, scaleOptions...); err != nil {
+			return err
+		}
+
+		res := &clusterScaleResult{
+			clusterRef:                clusterRef{Name: cluster.Name(), Namespace: cluster.Namespace()},
+			MachineDeploymentReplicas: clusterScaleCmdFlags.machineDeploymentReplicas,
+			Nodes:                     clusterScaleCmdFlags.group,
+		}
+
+		if clusterScaleCmdFlags.replicas >= 0 {
+			res.Replicas = &clusterScaleCmdFlags.replicas
+		}
+
+		return printResult(res)
 	},
 }
 
+// clusterScaleResult is the result of the scale command.
+type clusterScaleResult struct {
+	MachineDeploymentReplicas map[string]int `json:"machineDeploymentReplicas,omitempty"`
+	Replicas                  *int           `json:"replicas,omitempty"`
+	clusterRef
+
+	Nodes string `json:"nodes"`
+}
+
+func (res *clusterScaleResult) printTable(w io.Writer) error {
+	if res.Replicas != nil {
+		fmt.Fprintf(w, "scaled %s of cluster %s to %d replicas\n", res.Nodes, res.clusterRef, *res.Replicas) //nolint:errcheck
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(res.MachineDeploymentReplicas)) {
+		fmt.Fprintf(w, "scaled machine deployment %s of cluster %s to %d replicas\n", name, res.clusterRef, res.MachineDeploymentReplicas[name]) //nolint:errcheck
+	}
+
+	return nil
+}
+
+// inferScaleGroup picks the nodes group from the machine deployment and machine pool flags if --nodes is not set.
+func inferScaleGroup(explicit bool) error {
+	inferred := ""
+
+	switch {
+	case clusterScaleCmdFlags.machinePool != "":
+		inferred = "machine-pools"
+	case clusterScaleCmdFlags.machineDeployment != "",
+		clusterScaleCmdFlags.allMachineDeployments,
+		len(clusterScaleCmdFlags.machineDeploymentReplicas) > 0,
+		clusterScaleCmdFlags.selector != "":
+		inferred = "workers"
+	}
+
+	if inferred == "" {
+		return nil
+	}
+
+	if !explicit {
+		clusterScaleCmdFlags.group = inferred
+
+		return nil
+	}
+
+	if clusterScaleCmdFlags.group == "control-planes" {
+		return usageError("machine deployment and machine pool flags can not be used with --nodes control-planes")
+	}
+
+	return nil
+}
+
 func init() {
 	clusterCmd.AddCommand(clusterScaleCmd)
 
@@ -56,6 +165,13 @@ func init() {
 		&clusterScaleCmdFlags.group,
 		"nodes", "",
 		"control-planes",
-		"Nodes to scale; valid values are 'control-planes' or 'workers'",
+		"Nodes to scale; valid values are 'control-planes', 'workers' or 'machine-pools', inferred from the machine deployment and machine pool flags",
 	)
+	clusterScaleCmd.Flags().StringVar(&clusterScaleCmdFlags.machineDeployment, "machine-deployment", "", "Name of the machine deployment to scale")
+	clusterScaleCmd.Flags().StringVar(&clusterScaleCmdFlags.machinePool, "machine-pool", "", "Name of the machine pool to scale")
+	clusterScaleCmd.Flags().StringVar(&clusterScaleCmdFlags.selector, "selector", "", "Label selector of the machine deployments or machine pools to scale")
+	clusterScaleCmd.Flags().BoolVar(&clusterScaleCmdFlags.allMachineDeployments, "all-machine-deployments", false, "Scale all machine deployments of the cluster")
+	clusterScaleCmd.Flags().StringToIntVar(&clusterScaleCmdFlags.machineDeploymentReplicas, "machine-deployment-replicas", nil, "Per machine deployment replicas count, e.g. 'workers-a=3,workers-b=1'")
+	clusterScaleCmd.Flags().BoolVar(&clusterScaleCmdFlags.force, "force", false, "Scale machine deployments managed by the cluster autoscaler")
+	clusterScaleCmd.Flags().StringSliceVar(&clusterScaleCmdFlags.deleteMachines, "delete-machines", nil, "Machine or node names to remove when scaling down")
 }
@@ -0,0 +1,63 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+var clusterUpgradeCmdFlags struct {
+	kubernetesVersion string
+}
+
+var clusterUpgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade Kubernetes version of a CAPI cluster.",
+	Long:  ``,
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+
+		if clusterUpgradeCmdFlags.kubernetesVersion == "" {
+			return usageError("kubernetes version is required")
+		}
+
+		cluster, err := manager.NewCluster(ctx, clusterCmdFlags.clusterName, clusterCmdFlags.clusterNamespace)
+		if err != nil {
+			return err
+		}
+
+		if err = cluster.UpgradeKubernetes(ctx, clusterUpgradeCmdFlags.kubernetesVersion); err != nil {
+			return err
+		}
+
+		return printResult(&clusterUpgradeResult{
+			clusterRef:        clusterRef{Name: cluster.Name(), Namespace: cluster.Namespace()},
+			KubernetesVersion: clusterUpgradeCmdFlags.kubernetesVersion,
+		})
+	},
+}
+
+// clusterUpgradeResult is the result of the upgrade command.
+type clusterUpgradeResult struct {
+	clusterRef
+
+	KubernetesVersion string `json:"kubernetesVersion"`
+}
+
+func (res *clusterUpgradeResult) printTable(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "cluster %s is upgraded to Kubernetes %s\n", res.clusterRef, res.KubernetesVersion)
+
+	return err
+}
+
+func init() {
+	clusterCmd.AddCommand(clusterUpgradeCmd)
+
+	clusterUpgradeCmd.Flags().StringVar(&clusterUpgradeCmdFlags.kubernetesVersion, "kubernetes-version", "", "Kubernetes version to upgrade to")
+}
@@ -0,0 +1,29 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var configFlags struct {
+	overrides map[string]string
+	sources   []string
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the clusterctl configuration.",
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+
+	rootCmd.PersistentFlags().StringArrayVar(&configFlags.sources, "config-source", nil,
+		"Config source merged on top of the clusterctl config in order: file, URL, directory of overlays, secret://namespace/name or configmap://namespace/name")
+	rootCmd.PersistentFlags().StringToStringVar(&configFlags.overrides, "config-set", nil, "Config values overriding all config sources and the environment, e.g. 'AWS_REGION=us-east-1'")
+
+	configCmd.PersistentFlags().StringVar(&options.ClusterctlConfigPath, "clusterctl-config", options.ClusterctlConfigPath, "path to the clusterctl config file")
+}
@@ -0,0 +1,57 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/siderolabs/capi-utils/pkg/capi"
+)
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Print the effective clusterctl config values with their sources.",
+	Long:  `Secret values are redacted. Environment variables are shown only for the keys set by the config sources.`,
+	Example: `
+	capi config view --config-source ./overlays --config-source secret://capi-system/clusterctl --config-set AWS_REGION=us-east-1
+	`,
+	RunE: func(*cobra.Command, []string) error {
+		ctx := context.Background()
+
+		m, err := newManager(ctx, capi.Options{
+			ClusterctlConfigPath: options.ClusterctlConfigPath,
+		})
+		if err != nil {
+			return err
+		}
+
+		return printResult(&configViewResult{
+			Values: m.Config().View(),
+		})
+	},
+}
+
+// configViewResult is the result of the config view command.
+type configViewResult struct {
+	Values []capi.ConfigValue `json:"values"`
+}
+
+func (res *configViewResult) printTable(w io.Writer) error {
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE") //nolint:errcheck
+
+	for _, value := range res.Values {
+		fmt.Fprintf(w, "%s\t%v\t%s\n", value.Key, value.Value, value.Source) //nolint:errcheck
+	}
+
+	return nil
+}
+
+func init() {
+	configCmd.AddCommand(configViewCmd)
+}
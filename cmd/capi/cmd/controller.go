@@ -0,0 +1,104 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/siderolabs/capi-utils/pkg/capi"
+	"github.com/siderolabs/capi-utils/pkg/controller"
+	"github.com/siderolabs/capi-utils/pkg/controller/v1alpha1"
+)
+
+var controllerCmdFlags struct {
+	namespace   string
+	concurrency int
+	installCRDs bool
+	leaderElect bool
+}
+
+var controllerCmd = &cobra.Command{
+	Use:   "controller",
+	Short: "Run the controller reconciling TestCluster resources.",
+	Long:  `Each TestCluster is deployed as a CAPI cluster with the same name, scaled to the spec and destroyed on deletion or TTL expiry.`,
+	RunE: func(*cobra.Command, []string) error {
+		ctx := ctrl.SetupSignalHandler()
+
+		ctrl.SetLogger(logr.FromSlogHandler(logger.Handler()))
+
+		capiManager, err := newManager(ctx, capi.Options{})
+		if err != nil {
+			return err
+		}
+
+		client, err := capiManager.GetClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		if controllerCmdFlags.installCRDs {
+			if err = controller.InstallCRDs(ctx, client); err != nil {
+				return err
+			}
+		}
+
+		scheme := runtime.NewScheme()
+
+		if err = clientgoscheme.AddToScheme(scheme); err != nil {
+			return err
+		}
+
+		if err = v1alpha1.AddToScheme(scheme); err != nil {
+			return err
+		}
+
+		opts := ctrl.Options{
+			Scheme:           scheme,
+			LeaderElection:   controllerCmdFlags.leaderElect,
+			LeaderElectionID: "capi-utils-controller",
+			Metrics: metricsserver.Options{
+				BindAddress: "0",
+			},
+		}
+
+		if controllerCmdFlags.namespace != "" {
+			opts.Cache.DefaultNamespaces = map[string]cache.Config{
+				controllerCmdFlags.namespace: {},
+			}
+			opts.LeaderElectionNamespace = controllerCmdFlags.namespace
+		}
+
+		mgr, err := ctrl.NewManager(capiManager.GetRESTConfig(), opts)
+		if err != nil {
+			return err
+		}
+
+		reconciler := &controller.TestClusterReconciler{
+			Client:  mgr.GetClient(),
+			Manager: capiManager,
+		}
+
+		if err = reconciler.SetupWithManager(mgr, controllerCmdFlags.concurrency); err != nil {
+			return err
+		}
+
+		return mgr.Start(ctx)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(controllerCmd)
+
+	controllerCmd.Flags().StringVar(&controllerCmdFlags.namespace, "watch-namespace", "", "Namespace to watch TestClusters in, all namespaces by default")
+	controllerCmd.Flags().IntVar(&controllerCmdFlags.concurrency, "concurrency", 4, "Number of TestClusters reconciled in parallel")
+	controllerCmd.Flags().BoolVar(&controllerCmdFlags.installCRDs, "install-crds", true, "Create or update the TestCluster CRD on start")
+	controllerCmd.Flags().BoolVar(&controllerCmdFlags.leaderElect, "leader-elect", false, "Enable leader election for running several controller replicas")
+}
@@ -0,0 +1,11 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+var recordEvents bool
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&recordEvents, "record-events", false, "Record Kubernetes events on the Cluster objects for the lifecycle steps")
+}
@@ -0,0 +1,48 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"log/slog"
+	"os"
+)
+
+var loggingFlags struct {
+	format    string
+	verbosity int
+}
+
+var logger = slog.Default()
+
+// setupLogging creates the logger from the flags and makes it the default one,
+// so the standard library logger output is formatted the same way.
+func setupLogging() error {
+	opts := &slog.HandlerOptions{
+		// each verbosity step enables one more level: 0 is info, 1 is debug
+		Level: slog.LevelInfo - slog.Level(4*loggingFlags.verbosity),
+	}
+
+	var handler slog.Handler
+
+	switch loggingFlags.format {
+	case "text":
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		return usageError("log format can be either 'text' or 'json', got: %q", loggingFlags.format)
+	}
+
+	logger = slog.New(handler)
+
+	slog.SetDefault(logger)
+
+	return nil
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&loggingFlags.format, "log-format", "text", "Log format: text or json")
+	rootCmd.PersistentFlags().IntVarP(&loggingFlags.verbosity, "verbosity", "v", 0, "Log verbosity, 1 enables debug logs")
+}
@@ -0,0 +1,74 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/siderolabs/capi-utils/pkg/capi"
+)
+
+var metricsAddr string
+
+var metricsRegistry = prometheus.NewRegistry()
+
+// newManager creates the CAPI manager with the CLI logger, events and config settings and registers its metrics.
+func newManager(ctx context.Context, opts capi.Options) (*capi.Manager, error) {
+	opts.Logger = logger
+	opts.RecordEvents = recordEvents
+	opts.ConfigSources = configFlags.sources
+	opts.ConfigOverrides = configFlags.overrides
+
+	m, err := capi.NewManager(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = metricsRegistry.Register(capi.NewMetricsCollector(m)); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// serveMetrics starts the metrics endpoint if it is enabled.
+//
+// Kubernetes client and controller metrics registered by controller-runtime are served as well.
+func serveMetrics() {
+	if metricsAddr == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(prometheus.Gatherers{metricsRegistry, ctrlmetrics.Registry}, promhttp.HandlerOpts{}))
+
+	srv := &http.Server{
+		Addr:              metricsAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil {
+			logger.Error("failed to serve metrics", "error", err)
+		}
+	}()
+}
+
+func init() {
+	metricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	rootCmd.PersistentFlags().StringVar(&metricsAddr, "metrics-addr", "", "Address to serve Prometheus metrics on, e.g. :9996, disabled if empty")
+}
@@ -0,0 +1,150 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/siderolabs/capi-utils/pkg/capi"
+)
+
+// outputFormat is the value of the --output flag.
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+	outputYAML  outputFormat = "yaml"
+)
+
+func (f *outputFormat) String() string {
+	return string(*f)
+}
+
+func (f *outputFormat) Set(value string) error {
+	switch outputFormat(value) {
+	case outputTable, outputJSON, outputYAML:
+		*f = outputFormat(value)
+
+		return nil
+	default:
+		return fmt.Errorf("output format can be either 'table', 'json' or 'yaml', got: %q", value)
+	}
+}
+
+func (f *outputFormat) Type() string {
+	return "format"
+}
+
+var output = outputTable
+
+// result is the outcome of a command.
+//
+// Results are printed as JSON or YAML with their json tags, the fields are a stable interface for scripts.
+type result interface {
+	// printTable prints the result for humans.
+	printTable(w io.Writer) error
+}
+
+// printResult prints the result to stdout in the output format.
+func printResult(res result) error {
+	return writeResult(os.Stdout, res)
+}
+
+func writeResult(out io.Writer, res result) error {
+	switch output {
+	case outputJSON:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(res)
+	case outputYAML:
+		data, err := yaml.Marshal(res)
+		if err != nil {
+			return err
+		}
+
+		_, err = out.Write(data)
+
+		return err
+	default:
+		w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+
+		if err := res.printTable(w); err != nil {
+			return err
+		}
+
+		return w.Flush()
+	}
+}
+
+// errorResult is the machine-readable command error.
+type errorResult struct {
+	Error errorDetails `json:"error"`
+}
+
+type errorDetails struct {
+	Category capi.ErrorCategory `json:"category"`
+	Message  string             `json:"message"`
+}
+
+func (res *errorResult) printTable(w io.Writer) error {
+	_, err := fmt.Fprintln(w, res.Error.Message)
+
+	return err
+}
+
+// clusterRef identifies the cluster in the command results.
+type clusterRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+func (ref clusterRef) String() string {
+	return ref.Namespace + "/" + ref.Name
+}
+
+// printError prints the command error to stderr in the output format.
+//
+// Stdout is left to the result, so the partial results of the failed commands stay parseable.
+func printError(err error) {
+	res := &errorResult{
+		Error: errorDetails{
+			Category: capi.Category(err),
+			Message:  err.Error(),
+		},
+	}
+
+	if printErr := writeResult(os.Stderr, res); printErr != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
+
+// usageError is the command input validation error.
+func usageError(format string, args ...any) error {
+	return &capi.ValidationError{Err: fmt.Errorf(format, args...)}
+}
+
+// setupOutput keeps the usage out of the machine-readable output.
+func setupOutput() {
+	rootCmd.SilenceUsage = output != outputTable
+}
+
+func init() {
+	rootCmd.PersistentFlags().VarP(&output, "output", "o", "Output format: table, json or yaml")
+
+	cobra.OnInitialize(setupOutput)
+
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return &capi.ValidationError{Err: err}
+	})
+}
@@ -7,7 +7,6 @@ package cmd
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"os"
 
@@ -23,6 +22,19 @@ var rootCmd = &cobra.Command{
 	Use:   "capi",
 	Short: "CAPI is a tool to deploy CAPI and run integration tests against it.",
 	Long:  ``,
+	// errors are printed by Execute in the output format
+	SilenceErrors: true,
+	PersistentPreRunE: func(*cobra.Command, []string) error {
+		// logging goes first as the others log their failures
+		if err := setupLogging(); err != nil {
+			return err
+		}
+
+		serveMetrics()
+		setupTracing()
+
+		return nil
+	},
 }
 
 // Execute root command.
@@ -36,10 +48,19 @@ func Execute() {
 		}
 	}()
 
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+	err := rootCmd.Execute()
+
+	shutdownTracing()
+
+	if err != nil {
+		printError(err)
 		os.Exit(1)
 	}
 }
 
 var options = DefaultOptions()
+
+func init() {
+	// the subcommands persistent hooks run after the root ones
+	cobra.EnableTraverseRunHooks = true
+}
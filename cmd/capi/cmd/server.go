@@ -0,0 +1,95 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/siderolabs/capi-utils/pkg/capi"
+	"github.com/siderolabs/capi-utils/pkg/server"
+)
+
+var serverCmdFlags struct {
+	listenAddr         string
+	tokenFile          string
+	operationRetention time.Duration
+}
+
+var serverCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Serve the cluster lifecycle REST API.",
+	Long: `The OpenAPI spec of the API is served at /openapi.yaml.
+
+The /v1 API requires the bearer token read from --token-file.`,
+	RunE: func(*cobra.Command, []string) error {
+		ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer cancel()
+
+		tokenData, err := os.ReadFile(serverCmdFlags.tokenFile)
+		if err != nil {
+			return fmt.Errorf("failed to read the bearer token: %w", err)
+		}
+
+		token := strings.TrimSpace(string(tokenData))
+		if token == "" {
+			return usageError("bearer token file %q is empty", serverCmdFlags.tokenFile)
+		}
+
+		manager, err = newManager(ctx, capi.Options{})
+		if err != nil {
+			return err
+		}
+
+		srv := server.New(ctx, &server.ManagerBackend{Manager: manager},
+			server.WithOperationRetention(serverCmdFlags.operationRetention),
+			server.WithBearerToken(token),
+		)
+
+		httpServer := &http.Server{
+			Addr:              serverCmdFlags.listenAddr,
+			Handler:           srv.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		go func() {
+			<-ctx.Done()
+
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer shutdownCancel()
+
+			httpServer.Shutdown(shutdownCtx) //nolint:errcheck,contextcheck
+		}()
+
+		logger.Info("serving cluster API", "address", serverCmdFlags.listenAddr)
+
+		if err = httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+
+		// running operations are canceled with the context
+		srv.Wait()
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serverCmd)
+
+	serverCmd.Flags().StringVar(&serverCmdFlags.listenAddr, "listen", "127.0.0.1:8080", "Address to serve the API on")
+	serverCmd.Flags().StringVar(&serverCmdFlags.tokenFile, "token-file", "", "File with the bearer token required by the API requests")
+	serverCmd.MarkFlagRequired("token-file") //nolint:errcheck
+	serverCmd.Flags().DurationVar(&serverCmdFlags.operationRetention, "operation-retention", time.Hour, "How long the finished operations are kept")
+}
@@ -0,0 +1,73 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+var tracingFlags struct {
+	endpoint string
+	insecure bool
+}
+
+var tracerProvider *sdktrace.TracerProvider
+
+// setupTracing exports the spans to the OTLP endpoint if it is set, tracing is a no-op otherwise.
+func setupTracing() {
+	if tracingFlags.endpoint == "" {
+		return
+	}
+
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(tracingFlags.endpoint),
+	}
+
+	if tracingFlags.insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+
+	exporter, err := otlptracegrpc.New(context.Background(), opts...)
+	if err != nil {
+		logger.Error("failed to create the OTLP trace exporter", "error", err)
+
+		return
+	}
+
+	tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName("capi-utils"))),
+	)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+// shutdownTracing flushes the recorded spans.
+func shutdownTracing() {
+	if tracerProvider == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := tracerProvider.Shutdown(ctx); err != nil {
+		logger.Error("failed to flush the traces", "error", err)
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&tracingFlags.endpoint, "otlp-endpoint", "", "OTLP gRPC endpoint to export the traces to, e.g. localhost:4317, tracing is disabled if empty")
+	rootCmd.PersistentFlags().BoolVar(&tracingFlags.insecure, "otlp-insecure", false, "Use plain text connection to the OTLP endpoint")
+}
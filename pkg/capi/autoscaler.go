@@ -0,0 +1,484 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/siderolabs/go-retry/retry"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	clientcmd "k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// AutoscalerPlacement defines the cluster where the cluster autoscaler runs.
+type AutoscalerPlacement int
+
+const (
+	// AutoscalerInManagementCluster runs the autoscaler in the management cluster next to the CAPI objects.
+	AutoscalerInManagementCluster AutoscalerPlacement = iota
+	// AutoscalerInWorkloadCluster runs the autoscaler in the workload cluster.
+	//
+	// The management cluster API should be reachable from the workload cluster.
+	AutoscalerInWorkloadCluster
+)
+
+// DefaultAutoscalerImage is the cluster autoscaler image used by default.
+const DefaultAutoscalerImage = "registry.k8s.io/autoscaling/cluster-autoscaler:v1.32.1"
+
+// AutoscalerOptions defines cluster autoscaler deployment settings.
+type AutoscalerOptions struct {
+	Image     string
+	Placement AutoscalerPlacement
+}
+
+// AutoscalerOption optional cluster autoscaler parameter setter.
+type AutoscalerOption func(*AutoscalerOptions)
+
+// WithAutoscalerImage overrides the cluster autoscaler image.
+func WithAutoscalerImage(image string) AutoscalerOption {
+	return func(opts *AutoscalerOptions) {
+		opts.Image = image
+	}
+}
+
+// WithAutoscalerPlacement sets the cluster where the autoscaler is deployed.
+func WithAutoscalerPlacement(placement AutoscalerPlacement) AutoscalerOption {
+	return func(opts *AutoscalerOptions) {
+		opts.Placement = placement
+	}
+}
+
+// SetAutoscaling enables cluster autoscaler for the machine deployment.
+func (cluster *Cluster) SetAutoscaling(ctx context.Context, machineDeployment string, minSize, maxSize int) error {
+	if minSize < 0 || maxSize < minSize {
+		return fmt.Errorf("invalid autoscaling range %d..%d", minSize, maxSize)
+	}
+
+	return cluster.updateAutoscalingAnnotations(ctx, machineDeployment, func(annotations map[string]string) {
+		annotations[clusterv1.AutoscalerMinSizeAnnotation] = strconv.Itoa(minSize)
+		annotations[clusterv1.AutoscalerMaxSizeAnnotation] = strconv.Itoa(maxSize)
+	})
+}
+
+// DisableAutoscaling removes cluster autoscaler settings from the machine deployment.
+func (cluster *Cluster) DisableAutoscaling(ctx context.Context, machineDeployment string) error {
+	return cluster.updateAutoscalingAnnotations(ctx, machineDeployment, func(annotations map[string]string) {
+		delete(annotations, clusterv1.AutoscalerMinSizeAnnotation)
+		delete(annotations, clusterv1.AutoscalerMaxSizeAnnotation)
+	})
+}
+
+// DeployAutoscaler deploys the cluster autoscaler with the Cluster API provider for the cluster.
+func (cluster *Cluster) DeployAutoscaler(ctx context.Context, setters ...AutoscalerOption) error {
+	opts := AutoscalerOptions{
+		Image: DefaultAutoscalerImage,
+	}
+
+	for _, s := range setters {
+		s(&opts)
+	}
+
+	name := cluster.name + "-cluster-autoscaler"
+
+	args := []string{
+		"--cloud-provider=clusterapi",
+		fmt.Sprintf("--node-group-auto-discovery=clusterapi:namespace=%s,clusterName=%s", cluster.namespace, cluster.name),
+	}
+
+	var (
+		clientset *kubernetes.Clientset
+		namespace string
+		volume    corev1.Volume
+		mount     corev1.VolumeMount
+		err       error
+	)
+
+	switch opts.Placement {
+	case AutoscalerInManagementCluster:
+		clientset = cluster.manager.clientset
+		namespace = cluster.namespace
+
+		if err = cluster.createAutoscalerManagementRBAC(ctx, clientset, name, namespace); err != nil {
+			return err
+		}
+
+		volume = corev1.Volume{
+			Name: "kubeconfig",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: cluster.name + "-kubeconfig",
+				},
+			},
+		}
+		mount = corev1.VolumeMount{Name: volume.Name, MountPath: "/etc/kubernetes/workload", ReadOnly: true}
+
+		args = append(args, "--kubeconfig=/etc/kubernetes/workload/value", "--clusterapi-cloud-config-authoritative")
+	case AutoscalerInWorkloadCluster:
+		namespace = metav1.NamespaceSystem
+
+		if clientset, err = cluster.KubernetesClientset(ctx); err != nil {
+			return err
+		}
+
+		if err = cluster.createAutoscalerWorkloadRBAC(ctx, clientset, name, namespace); err != nil {
+			return err
+		}
+
+		var managementKubeconfig []byte
+
+		if managementKubeconfig, err = cluster.manager.managementKubeconfig(ctx); err != nil {
+			return err
+		}
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: name + "-management-kubeconfig", Namespace: namespace},
+			Data: map[string][]byte{
+				"kubeconfig": managementKubeconfig,
+			},
+		}
+
+		if err = createOrUpdate(ctx, clientset.CoreV1().Secrets(namespace).Create, clientset.CoreV1().Secrets(namespace).Update, secret); err != nil {
+			return err
+		}
+
+		volume = corev1.Volume{
+			Name: "management-kubeconfig",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: secret.Name,
+				},
+			},
+		}
+		mount = corev1.VolumeMount{Name: volume.Name, MountPath: "/etc/kubernetes/management", ReadOnly: true}
+
+		args = append(args, "--cloud-config=/etc/kubernetes/management/kubeconfig")
+	default:
+		return fmt.Errorf("unknown autoscaler placement %d", opts.Placement)
+	}
+
+	labels := map[string]string{
+		"app.kubernetes.io/name":     "cluster-autoscaler",
+		"app.kubernetes.io/instance": name,
+	}
+
+	replicas := int32(1)
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: name,
+					Containers: []corev1.Container{
+						{
+							Name:         "cluster-autoscaler",
+							Image:        opts.Image,
+							Command:      []string{"/cluster-autoscaler"},
+							Args:         args,
+							VolumeMounts: []corev1.VolumeMount{mount},
+						},
+					},
+					Volumes: []corev1.Volume{volume},
+				},
+			},
+		},
+	}
+
+	deployments := clientset.AppsV1().Deployments(namespace)
+
+	if err = createOrUpdate(ctx, deployments.Create, deployments.Update, deployment); err != nil {
+		return err
+	}
+
+	return retry.Constant(10*time.Minute, retry.WithUnits(10*time.Second)).Retry(logRetries(cluster.logger(), func() error {
+		if deployment, err = deployments.Get(ctx, name, metav1.GetOptions{}); err != nil {
+			return retry.ExpectedError(err)
+		}
+
+		if deployment.Status.ReadyReplicas != deployment.Status.Replicas || deployment.Status.ReadyReplicas == 0 {
+			return retry.ExpectedError(fmt.Errorf("%d of %d replicas ready", deployment.Status.ReadyReplicas, deployment.Status.Replicas))
+		}
+
+		return nil
+	}))
+}
+
+func (cluster *Cluster) updateAutoscalingAnnotations(ctx context.Context, machineDeployment string, update func(map[string]string)) error {
+	machineDeployments, err := cluster.Workers(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range machineDeployments.Items {
+		deployment := &machineDeployments.Items[i]
+
+		if deployment.GetName() != machineDeployment {
+			continue
+		}
+
+		annotations := deployment.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		update(annotations)
+
+		deployment.SetAnnotations(annotations)
+
+		return cluster.manager.runtimeClient.Update(ctx, deployment)
+	}
+
+	return fmt.Errorf("machine deployment %q is not found", machineDeployment)
+}
+
+// createAutoscalerManagementRBAC allows the autoscaler to manage the CAPI objects in the cluster namespace.
+func (cluster *Cluster) createAutoscalerManagementRBAC(ctx context.Context, clientset *kubernetes.Clientset, name, namespace string) error {
+	serviceAccounts := clientset.CoreV1().ServiceAccounts(namespace)
+
+	if err := createOrUpdate(ctx, serviceAccounts.Create, serviceAccounts.Update, &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}); err != nil {
+		return err
+	}
+
+	roles := clientset.RbacV1().Roles(namespace)
+
+	if err := createOrUpdate(ctx, roles.Create, roles.Update, &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{clusterv1.GroupVersion.Group},
+				Resources: []string{"*"},
+				Verbs:     []string{"get", "list", "watch", "update", "patch"},
+			},
+			{
+				APIGroups: []string{"infrastructure.cluster.x-k8s.io"},
+				Resources: []string{"*"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		},
+	}); err != nil {
+		return err
+	}
+
+	roleBindings := clientset.RbacV1().RoleBindings(namespace)
+
+	return createOrUpdate(ctx, roleBindings.Create, roleBindings.Update, &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+		Subjects: []rbacv1.Subject{
+			{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: namespace},
+		},
+	})
+}
+
+// createAutoscalerWorkloadRBAC allows the autoscaler to manage the workload cluster nodes and pods.
+func (cluster *Cluster) createAutoscalerWorkloadRBAC(ctx context.Context, clientset *kubernetes.Clientset, name, namespace string) error {
+	serviceAccounts := clientset.CoreV1().ServiceAccounts(namespace)
+
+	if err := createOrUpdate(ctx, serviceAccounts.Create, serviceAccounts.Update, &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}); err != nil {
+		return err
+	}
+
+	clusterRoles := clientset.RbacV1().ClusterRoles()
+
+	if err := createOrUpdate(ctx, clusterRoles.Create, clusterRoles.Update, &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Rules:      autoscalerWorkloadClusterRules,
+	}); err != nil {
+		return err
+	}
+
+	clusterRoleBindings := clientset.RbacV1().ClusterRoleBindings()
+
+	if err := createOrUpdate(ctx, clusterRoleBindings.Create, clusterRoleBindings.Update, &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     name,
+		},
+		Subjects: []rbacv1.Subject{
+			{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: namespace},
+		},
+	}); err != nil {
+		return err
+	}
+
+	roles := clientset.RbacV1().Roles(namespace)
+
+	if err := createOrUpdate(ctx, roles.Create, roles.Update, &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Rules:      autoscalerWorkloadRules,
+	}); err != nil {
+		return err
+	}
+
+	roleBindings := clientset.RbacV1().RoleBindings(namespace)
+
+	return createOrUpdate(ctx, roleBindings.Create, roleBindings.Update, &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+		Subjects: []rbacv1.Subject{
+			{Kind: rbacv1.ServiceAccountKind, Name: name, Namespace: namespace},
+		},
+	})
+}
+
+// autoscalerWorkloadClusterRules are the upstream cluster-autoscaler ClusterRole rules.
+var autoscalerWorkloadClusterRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{""},
+		Resources: []string{"events", "endpoints"},
+		Verbs:     []string{"create", "patch"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"pods/eviction"},
+		Verbs:     []string{"create"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"pods/status"},
+		Verbs:     []string{"update"},
+	},
+	{
+		APIGroups:     []string{""},
+		Resources:     []string{"endpoints"},
+		ResourceNames: []string{"cluster-autoscaler"},
+		Verbs:         []string{"get", "update"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"nodes"},
+		Verbs:     []string{"watch", "list", "get", "update"},
+	},
+	{
+		APIGroups: []string{""},
+		Resources: []string{"namespaces", "pods", "services", "replicationcontrollers", "persistentvolumeclaims", "persistentvolumes"},
+		Verbs:     []string{"watch", "list", "get"},
+	},
+	{
+		APIGroups: []string{"extensions"},
+		Resources: []string{"replicasets", "daemonsets"},
+		Verbs:     []string{"watch", "list", "get"},
+	},
+	{
+		APIGroups: []string{"policy"},
+		Resources: []string{"poddisruptionbudgets"},
+		Verbs:     []string{"watch", "list"},
+	},
+	{
+		APIGroups: []string{"apps"},
+		Resources: []string{"statefulsets", "replicasets", "daemonsets"},
+		Verbs:     []string{"watch", "list", "get"},
+	},
+	{
+		APIGroups: []string{"storage.k8s.io"},
+		Resources: []string{"storageclasses", "csinodes", "csidrivers", "csistoragecapacities"},
+		Verbs:     []string{"watch", "list", "get"},
+	},
+	{
+		APIGroups: []string{"batch"},
+		Resources: []string{"jobs", "cronjobs"},
+		Verbs:     []string{"watch", "list", "get"},
+	},
+	{
+		APIGroups: []string{"coordination.k8s.io"},
+		Resources: []string{"leases"},
+		Verbs:     []string{"create"},
+	},
+	{
+		APIGroups:     []string{"coordination.k8s.io"},
+		Resources:     []string{"leases"},
+		ResourceNames: []string{"cluster-autoscaler"},
+		Verbs:         []string{"get", "update"},
+	},
+}
+
+// autoscalerWorkloadRules are the upstream cluster-autoscaler Role rules for the status and priority expander config maps.
+var autoscalerWorkloadRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{""},
+		Resources: []string{"configmaps"},
+		Verbs:     []string{"create", "list", "watch"},
+	},
+	{
+		APIGroups:     []string{""},
+		Resources:     []string{"configmaps"},
+		ResourceNames: []string{"cluster-autoscaler-status", "cluster-autoscaler-priority-expander"},
+		Verbs:         []string{"delete", "get", "update", "watch"},
+	},
+}
+
+// managementKubeconfig returns the minified kubeconfig of the management cluster.
+func (clusterAPI *Manager) managementKubeconfig(ctx context.Context) ([]byte, error) {
+	kubeconfig, err := clusterAPI.GetKubeconfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := clientcmd.LoadFromFile(kubeconfig.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if kubeconfig.Context != "" {
+		config.CurrentContext = kubeconfig.Context
+	}
+
+	if err = clientcmdapi.MinifyConfig(config); err != nil {
+		return nil, err
+	}
+
+	if err = clientcmdapi.FlattenConfig(config); err != nil {
+		return nil, err
+	}
+
+	return clientcmd.Write(*config)
+}
+
+func createOrUpdate[T any](
+	ctx context.Context,
+	create func(context.Context, T, metav1.CreateOptions) (T, error),
+	update func(context.Context, T, metav1.UpdateOptions) (T, error),
+	object T,
+) error {
+	_, err := create(ctx, object, metav1.CreateOptions{})
+	if err == nil {
+		return nil
+	}
+
+	if !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	_, err = update(ctx, object, metav1.UpdateOptions{})
+
+	return err
+}
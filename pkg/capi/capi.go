@@ -8,6 +8,7 @@ package capi
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -23,6 +24,7 @@ import (
 	"k8s.io/client-go/rest"
 	clientcmd "k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/tools/record"
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
@@ -40,6 +42,8 @@ type Manager struct {
 	clientset     *kubernetes.Clientset
 	config        *rest.Config
 	runtimeClient runtimeclient.Client
+	logger        *slog.Logger
+	recorder      record.EventRecorder
 	version       string
 	providers     []infrastructure.Provider
 	cfg           *Config
@@ -49,50 +53,48 @@ type Manager struct {
 
 // Options for the CAPI installer.
 type Options struct {
-	Proxy                   cluster.Proxy
-	Kubeconfig              client.Kubeconfig
+	Proxy      cluster.Proxy
+	Kubeconfig client.Kubeconfig
+	// Logger is used for all manager and cluster logs, defaults to slog.Default().
+	Logger *slog.Logger
+	// EventRecorder records the lifecycle events on the Cluster objects.
+	EventRecorder record.EventRecorder
+	// ConfigOverrides take precedence over the clusterctl config, the config sources and the environment.
+	ConfigOverrides         map[string]string
 	ClusterctlConfigPath    string
 	CoreProvider            string
 	ContextName             string
 	InfrastructureProviders []infrastructure.Provider
 	BootstrapProviders      []string
 	ControlPlaneProviders   []string
-	WaitProviderTimeout     time.Duration
+	// ConfigSources are merged on top of the clusterctl config in order, see Config.Load.
+	ConfigSources       []string
+	WaitProviderTimeout time.Duration
+	// RecordEvents records the lifecycle events to the management cluster if EventRecorder is not set,
+	// each event is written before the recording call returns.
+	RecordEvents bool
 }
 
 // NewManager creates new Manager object.
-func NewManager(ctx context.Context, options Options) (*Manager, error) {
+func NewManager(ctx context.Context, options Options) (_ *Manager, err error) {
+	ctx, span := startSpan(ctx, "NewManager")
+	defer func() { endSpan(span, err) }()
+
 	clusterAPI := &Manager{
 		options: options,
 		cfg:     newConfig(),
+		logger:  options.Logger,
 	}
 
-	err := clusterAPI.cfg.Init(ctx, options.ClusterctlConfigPath)
-	if err != nil {
-		return nil, err
+	if clusterAPI.logger == nil {
+		clusterAPI.logger = slog.Default()
 	}
 
-	configClient, err := config.New(ctx, options.ClusterctlConfigPath, config.InjectReader(clusterAPI.cfg))
-	if err != nil {
+	if err = clusterAPI.cfg.Init(ctx, options.ClusterctlConfigPath); err != nil {
 		return nil, err
 	}
 
-	opts := []client.Option{
-		client.InjectConfig(configClient),
-	}
-
-	if options.Proxy != nil {
-		opts = append(opts, client.InjectClusterClientFactory(func(input client.ClusterClientFactoryInput) (cluster.Client, error) {
-			return cluster.New(
-				cluster.Kubeconfig(input.Kubeconfig),
-				configClient,
-				cluster.InjectYamlProcessor(input.Processor),
-				cluster.InjectProxy(options.Proxy),
-			), nil
-		}))
-	}
-
-	clusterAPI.client, err = client.New(ctx, options.ClusterctlConfigPath, opts...)
+	clusterAPI.client, err = clusterAPI.newClusterctlClient(ctx, clusterAPI.cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -132,6 +134,13 @@ func NewManager(ctx context.Context, options Options) (*Manager, error) {
 		return nil, err
 	}
 
+	// clusterctl reads the config lazily, so the sources in the management cluster are loaded once it is connected
+	if err = clusterAPI.cfg.Load(ctx, clusterAPI.clientset, options.ConfigSources...); err != nil {
+		return nil, err
+	}
+
+	clusterAPI.cfg.Override(options.ConfigOverrides)
+
 	_, err = clusterAPI.GetClient(ctx)
 	if err != nil {
 		return nil, err
@@ -141,9 +150,36 @@ func NewManager(ctx context.Context, options Options) (*Manager, error) {
 		return nil, err
 	}
 
+	clusterAPI.setupEvents()
+
 	return clusterAPI, nil
 }
 
+// newClusterctlClient creates the clusterctl client reading the config.
+func (clusterAPI *Manager) newClusterctlClient(ctx context.Context, cfg *Config) (client.Client, error) {
+	configClient, err := config.New(ctx, clusterAPI.options.ClusterctlConfigPath, config.InjectReader(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []client.Option{
+		client.InjectConfig(configClient),
+	}
+
+	if clusterAPI.options.Proxy != nil {
+		opts = append(opts, client.InjectClusterClientFactory(func(input client.ClusterClientFactoryInput) (cluster.Client, error) {
+			return cluster.New(
+				cluster.Kubeconfig(input.Kubeconfig),
+				configClient,
+				cluster.InjectYamlProcessor(input.Processor),
+				cluster.InjectProxy(clusterAPI.options.Proxy),
+			), nil
+		}))
+	}
+
+	return client.New(ctx, clusterAPI.options.ClusterctlConfigPath, opts...)
+}
+
 // GetKubeconfig returns kubeconfig in clusterctl expected format.
 func (clusterAPI *Manager) GetKubeconfig(context.Context) (client.Kubeconfig, error) {
 	if clusterAPI.kubeconfig.Path != "" {
@@ -185,13 +221,35 @@ func (clusterAPI *Manager) GetClient(context.Context) (client runtimeclient.Clie
 	return clusterAPI.runtimeClient, err
 }
 
+// Config returns the effective clusterctl config.
+func (clusterAPI *Manager) Config() *Config {
+	return clusterAPI.cfg
+}
+
+// GetRESTConfig returns the management cluster REST config.
+func (clusterAPI *Manager) GetRESTConfig() *rest.Config {
+	return clusterAPI.config
+}
+
 // GetClientSet returns a kubernetes clientset to use.
 func (clusterAPI *Manager) GetClientSet() *kubernetes.Clientset {
 	return clusterAPI.clientset
 }
 
 // Install the Manager components and wait for them to be ready.
-func (clusterAPI *Manager) Install(ctx context.Context) error {
+func (clusterAPI *Manager) Install(ctx context.Context) (err error) {
+	providers := make([]string, 0, len(clusterAPI.options.InfrastructureProviders))
+
+	for _, provider := range clusterAPI.options.InfrastructureProviders {
+		providers = append(providers, provider.Name())
+	}
+
+	metric := startOperationMetric(operationInstall, strings.Join(providers, ","), "")
+	defer func() { metric.finish(err) }()
+
+	ctx, span := startSpan(ctx, "Install", providerKey.String(strings.Join(providers, ",")))
+	defer func() { endSpan(span, err) }()
+
 	kubeconfig, err := clusterAPI.GetKubeconfig(ctx)
 	if err != nil {
 		return err
@@ -224,14 +282,17 @@ func (clusterAPI *Manager) Install(ctx context.Context) error {
 }
 
 // InstallCore installs only core, global watched components (capi, cabpt, cacppt).
-func (clusterAPI *Manager) InstallCore(ctx context.Context, kubeconfig client.Kubeconfig) error {
+func (clusterAPI *Manager) InstallCore(ctx context.Context, kubeconfig client.Kubeconfig) (err error) {
+	ctx, span := startSpan(ctx, "InstallCore")
+	defer func() { endSpan(span, err) }()
+
 	installed, err := isCoreInstalled(ctx, clusterAPI.clientset)
 	if err != nil {
 		return err
 	}
 
 	if !installed {
-		fmt.Println("initializing the core capi components")
+		clusterAPI.logger.Info("initializing the core capi components", logKeyPhase, "install")
 		// Initialize everything but the infra providers, as we want to specify target
 		// namespaces for those.
 		coreOpts := client.InitOptions{
@@ -259,11 +320,11 @@ func (clusterAPI *Manager) InstallCore(ctx context.Context, kubeconfig client.Ku
 
 // InstallProvider installs a specific infrastructure provider and allows namespacing of
 // the provider itself and its "watches".
-func (clusterAPI *Manager) InstallProvider(ctx context.Context, kubeconfig client.Kubeconfig, provider infrastructure.Provider) error {
-	var (
-		installed bool
-		err       error
-	)
+func (clusterAPI *Manager) InstallProvider(ctx context.Context, kubeconfig client.Kubeconfig, provider infrastructure.Provider) (err error) {
+	ctx, span := startSpan(ctx, "InstallProvider", providerKey.String(provider.Name()))
+	defer func() { endSpan(span, err) }()
+
+	var installed bool
 
 	providerString := provider.Name()
 
@@ -276,10 +337,11 @@ func (clusterAPI *Manager) InstallProvider(ctx context.Context, kubeconfig clien
 	}
 
 	if !installed {
-		fmt.Printf("initializing infrastructure provider %s\n", providerString)
+		clusterAPI.logger.Info("initializing infrastructure provider", logKeyPhase, "install", logKeyProvider, providerString)
 
-		vars, err := provider.ProviderVars()
-		if err != nil {
+		var vars infrastructure.Variables
+
+		if vars, err = provider.ProviderVars(); err != nil {
 			return err
 		}
 
@@ -311,7 +373,10 @@ func (clusterAPI *Manager) InstallProvider(ctx context.Context, kubeconfig clien
 // FetchState fetches infra providers and installed CAPI version if any.
 //
 //nolint:gocognit
-func (clusterAPI *Manager) FetchState(ctx context.Context) error {
+func (clusterAPI *Manager) FetchState(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "FetchState")
+	defer func() { endSpan(span, err) }()
+
 	resources, err := clusterAPI.clientset.ServerPreferredResources()
 	if err != nil {
 		return err
@@ -381,7 +446,7 @@ func (clusterAPI *Manager) FetchState(ctx context.Context) error {
 				return fieldNotFound("providerVersion")
 			}
 
-			provider, err := infrastructure.NewProvider(fmt.Sprintf("%s:%s", providerName, providerVersion))
+			provider, err := infrastructure.NewProvider(fmt.Sprintf("%s:%s", providerName, providerVersion), infrastructure.WithLogger(clusterAPI.logger))
 			// if we couldn't parse it then it's not supported
 			if err != nil {
 				continue
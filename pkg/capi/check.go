@@ -12,19 +12,25 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 )
 
 // CheckClusterReady verifies that cluster ready from the CAPI point of view.
 //
+// Each call is a single check, the checks finding the cluster not ready are reported with the error outcome.
+//
 //nolint:cyclop,gocyclo,gocognit
-func (clusterAPI *Manager) CheckClusterReady(ctx context.Context, cluster *Cluster) error {
+func (clusterAPI *Manager) CheckClusterReady(ctx context.Context, cluster *Cluster) (err error) {
+	metric := startOperationMetric(operationCheckReady, cluster.providerLabel(), clusterLabel(cluster.name, cluster.namespace))
+	defer func() { metric.finish(err) }()
+
 	var (
 		initialized bool
 		ready       bool
 		found       bool
 		conditions  []any
-		err         error
 	)
+
 	if err = cluster.sync(ctx); err != nil {
 		return err
 	}
@@ -119,6 +125,29 @@ func (clusterAPI *Manager) CheckClusterReady(ctx context.Context, cluster *Clust
 		}
 	}
 
+	machinePools, err := cluster.MachinePools(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, machinePool := range machinePools.Items {
+		var phase string
+
+		if phase, found, err = unstructured.NestedString(machinePool.Object, "status", "phase"); err != nil {
+			return err
+		} else if !found {
+			return retry.ExpectedError(fieldNotFound("status", "phase"))
+		}
+
+		if expv1.MachinePoolPhase(phase) != expv1.MachinePoolPhaseRunning {
+			return retry.ExpectedError(fmt.Errorf("machinePool phase is %s", phase))
+		}
+
+		if err = checkReplicasReady(machinePool); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -8,7 +8,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"os"
 	"time"
 
 	"github.com/siderolabs/go-retry/retry"
@@ -18,6 +17,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	clientcmd "k8s.io/client-go/tools/clientcmd"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capiclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client"
 	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -59,7 +60,10 @@ func (clusterAPI *Manager) NewCluster(ctx context.Context, name, namespace strin
 // Sync updates nodes pool and recreates talos client.
 //
 //nolint:gocyclo,cyclop
-func (cluster *Cluster) Sync(ctx context.Context) error {
+func (cluster *Cluster) Sync(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "Sync", cluster.spanAttributes()...)
+	defer func() { endSpan(span, err) }()
+
 	var (
 		controlPlane unstructured.Unstructured
 		machines     unstructured.UnstructuredList
@@ -80,7 +84,6 @@ func (cluster *Cluster) Sync(ctx context.Context) error {
 	var (
 		controlPlaneSelector string
 		found                bool
-		err                  error
 	)
 
 	controlPlaneRef, err := getRef(cluster.cluster.Object, "spec", "controlPlaneRef")
@@ -127,28 +130,7 @@ func (cluster *Cluster) Sync(ctx context.Context) error {
 		return err
 	}
 
-	kubeconfig, err := cluster.manager.GetKubeconfig(ctx)
-	if err != nil {
-		return err
-	}
-
-	options := capiclient.GetKubeconfigOptions{
-		Kubeconfig:          kubeconfig,
-		WorkloadClusterName: cluster.name,
-		Namespace:           cluster.namespace,
-	}
-
-	raw, err := cluster.manager.client.GetKubeconfig(ctx, options)
-	if err != nil {
-		return err
-	}
-
-	config, err := clientcmd.RESTConfigFromKubeConfig([]byte(raw))
-	if err != nil {
-		return err
-	}
-
-	clientset, err := kubernetes.NewForConfig(config)
+	clientset, err := cluster.KubernetesClientset(ctx)
 	if err != nil {
 		return err
 	}
@@ -193,6 +175,37 @@ func (cluster *Cluster) Sync(ctx context.Context) error {
 	return nil
 }
 
+// Kubeconfig returns kubeconfig of the workload cluster.
+func (cluster *Cluster) Kubeconfig(ctx context.Context) (string, error) {
+	kubeconfig, err := cluster.manager.GetKubeconfig(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	options := capiclient.GetKubeconfigOptions{
+		Kubeconfig:          kubeconfig,
+		WorkloadClusterName: cluster.name,
+		Namespace:           cluster.namespace,
+	}
+
+	return cluster.manager.client.GetKubeconfig(ctx, options)
+}
+
+// KubernetesClientset returns kubernetes clientset for the workload cluster.
+func (cluster *Cluster) KubernetesClientset(ctx context.Context) (*kubernetes.Clientset, error) {
+	raw, err := cluster.Kubeconfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := clientcmd.RESTConfigFromKubeConfig([]byte(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	return kubernetes.NewForConfig(config)
+}
+
 // TalosClient returns new talos client for the CAPI cluster.
 func (cluster *Cluster) TalosClient(ctx context.Context) (*talosclient.Client, error) {
 	if cluster.client != nil {
@@ -220,14 +233,32 @@ func (cluster *Cluster) TalosConfig(ctx context.Context) (*clientconfig.Config,
 }
 
 // Health runs the healthcheck for the cluster.
-func (cluster *Cluster) Health(ctx context.Context) error {
-	return retry.Constant(5*time.Minute, retry.WithUnits(10*time.Second)).RetryWithContext(ctx, func(ctx context.Context) error {
+func (cluster *Cluster) Health(ctx context.Context) (err error) {
+	metric := startOperationMetric(operationHealth, cluster.providerLabel(), clusterLabel(cluster.name, cluster.namespace))
+	defer func() { metric.finish(err) }()
+
+	ctx, span := startSpan(ctx, "Health", cluster.spanAttributes()...)
+	defer func() { endSpan(span, err) }()
+
+	if err = retry.Constant(5*time.Minute, retry.WithUnits(10*time.Second)).RetryWithContext(ctx, func(ctx context.Context) error {
 		// retry health checks as sometimes bootstrap bootkube issues break the check
 		return retry.ExpectedError(cluster.health(ctx))
-	})
+	}); err != nil {
+		cluster.recordEvent(v1.EventTypeWarning, EventReasonHealthCheckFailed, "Health check failed: %s", err)
+
+		return err
+	}
+
+	cluster.recordEvent(v1.EventTypeNormal, EventReasonHealthCheckPassed, "Health check passed")
+
+	return nil
 }
 
-func (cluster *Cluster) health(ctx context.Context) error {
+// health runs a single Talos health check, the check messages are recorded as the span events.
+func (cluster *Cluster) health(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "HealthCheck", cluster.spanAttributes()...)
+	defer func() { endSpan(span, err) }()
+
 	client, err := cluster.TalosClient(ctx)
 	if err != nil {
 		return err
@@ -241,25 +272,27 @@ func (cluster *Cluster) health(ctx context.Context) error {
 		return err
 	}
 
-	if err := resp.CloseSend(); err != nil {
+	if err = resp.CloseSend(); err != nil {
 		return err
 	}
 
 	for {
-		msg, err := resp.Recv()
-		if err != nil {
-			if err == io.EOF || status.Code(err) == codes.Canceled { //nolint:errorlint
+		msg, recvErr := resp.Recv()
+		if recvErr != nil {
+			if recvErr == io.EOF || status.Code(recvErr) == codes.Canceled { //nolint:errorlint
 				return nil
 			}
 
-			return err
+			return recvErr
 		}
 
 		if msg.GetMetadata().GetError() != "" {
 			return fmt.Errorf("healthcheck error: %s", msg.GetMetadata().GetError())
 		}
 
-		fmt.Fprintln(os.Stderr, msg.GetMessage())
+		span.AddEvent(msg.GetMessage())
+
+		cluster.logger().Info(msg.GetMessage(), logKeyPhase, "health")
 	}
 }
 
@@ -273,6 +306,13 @@ func (cluster *Cluster) Namespace() string {
 	return cluster.namespace
 }
 
+// TopologyManaged returns true if the cluster is created from a ClusterClass.
+func (cluster *Cluster) TopologyManaged() bool {
+	_, found, err := unstructured.NestedMap(cluster.cluster.Object, "spec", "topology")
+
+	return err == nil && found
+}
+
 // ControlPlanes gets controlplane object from the management cluster.
 func (cluster *Cluster) ControlPlanes(ctx context.Context) (*unstructured.Unstructured, error) {
 	controlPlaneRef, err := getRef(cluster.cluster.Object, "spec", "controlPlaneRef")
@@ -315,6 +355,56 @@ func (cluster *Cluster) Workers(ctx context.Context) (*unstructured.Unstructured
 	return &machineDeployments, nil
 }
 
+// MachinePools gets MachinePool list from the management cluster.
+//
+// Returns an empty list if MachinePool CRDs are not installed.
+func (cluster *Cluster) MachinePools(ctx context.Context) (*unstructured.UnstructuredList, error) {
+	var machinePools unstructured.UnstructuredList
+
+	machinePools.SetGroupVersionKind(
+		schema.GroupVersionKind{
+			Version: cluster.manager.version,
+			Group:   "cluster.x-k8s.io",
+			Kind:    "MachinePool",
+		},
+	)
+
+	if err := cluster.manager.runtimeClient.List(ctx, &machinePools,
+		runtimeclient.InNamespace(cluster.namespace),
+		runtimeclient.MatchingLabels{clusterv1.ClusterNameLabel: cluster.name},
+	); err != nil {
+		if meta.IsNoMatchError(err) {
+			return &machinePools, nil
+		}
+
+		return nil, err
+	}
+
+	return &machinePools, nil
+}
+
+// Machines gets Machine list from the management cluster.
+func (cluster *Cluster) Machines(ctx context.Context) (*unstructured.UnstructuredList, error) {
+	var machines unstructured.UnstructuredList
+
+	machines.SetGroupVersionKind(
+		schema.GroupVersionKind{
+			Version: cluster.manager.version,
+			Group:   "cluster.x-k8s.io",
+			Kind:    "Machine",
+		},
+	)
+
+	if err := cluster.manager.runtimeClient.List(ctx, &machines,
+		runtimeclient.InNamespace(cluster.namespace),
+		runtimeclient.MatchingLabels{clusterv1.ClusterNameLabel: cluster.name},
+	); err != nil {
+		return nil, err
+	}
+
+	return &machines, nil
+}
+
 func (cluster *Cluster) sync(ctx context.Context) error {
 	cluster.cluster.SetGroupVersionKind(
 		schema.GroupVersionKind{
@@ -5,27 +5,55 @@
 package capi
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/util/homedir"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 )
 
+const (
+	secretConfigScheme    = "secret://"
+	configMapConfigScheme = "configmap://"
+
+	// configSourceEnv and configSourceOverride are the sources of the environment and Set values.
+	configSourceEnv      = "env"
+	configSourceOverride = "override"
+
+	redactedConfigValue = "<redacted>"
+)
+
 // Config custom implementation of config reader for clusterctl.
 type Config struct {
-	config      *viper.Viper
+	config *viper.Viper
+	// sources maps the keys to the source which set the effective value.
+	sources map[string]string
+	// secretKeys are loaded from the Secrets and redacted in the config view.
+	secretKeys  map[string]struct{}
 	configPaths []string
 }
 
+// ConfigValue is the effective config value with its source.
+type ConfigValue struct {
+	Value    any    `json:"value"`
+	Key      string `json:"key"`
+	Source   string `json:"source"`
+	Redacted bool   `json:"redacted,omitempty"`
+}
+
 func newConfig() *Config {
 	c := viper.New()
 
@@ -37,6 +65,8 @@ func newConfig() *Config {
 
 	return &Config{
 		config:      c,
+		sources:     map[string]string{},
+		secretKeys:  map[string]struct{}{},
 		configPaths: []string{filepath.Join(homedir.HomeDir(), config.ConfigFolder)},
 	}
 }
@@ -51,30 +81,20 @@ func (c *Config) Init(ctx context.Context, path string) error {
 
 		switch url.Scheme {
 		case "https", "http":
-			client := &http.Client{
-				Timeout: 30 * time.Second,
-			}
-
-			request, err := http.NewRequestWithContext(ctx, http.MethodGet, url.String(), nil)
+			data, err := fetchConfig(ctx, url.String())
 			if err != nil {
 				return err
 			}
 
-			resp, err := client.Do(request)
-			if err != nil {
-				return fmt.Errorf("failed to download the clusterctl config file from %s %w", url, err)
-			}
+			c.config.SetConfigType("yaml")
 
-			if resp.StatusCode != http.StatusOK {
-				return fmt.Errorf("failed to download the clusterctl config file from %s got %d", url, resp.StatusCode)
+			if err = c.config.ReadConfig(bytes.NewReader(data)); err != nil {
+				return err
 			}
 
-			defer io.Copy(io.Discard, resp.Body) //nolint:errcheck
-			defer resp.Body.Close()              //nolint:errcheck
+			c.trackSource("", c.config.AllSettings(), url.String())
 
-			if err = c.config.ReadConfig(resp.Body); err != nil {
-				return err
-			}
+			return nil
 		default:
 			if _, err := os.Stat(path); err != nil {
 				return fmt.Errorf("failed to check if clusterctl config file exists %w", err)
@@ -96,7 +116,252 @@ func (c *Config) Init(ctx context.Context, path string) error {
 		}
 	}
 
-	return c.config.ReadInConfig()
+	if err := c.config.ReadInConfig(); err != nil {
+		return err
+	}
+
+	c.trackSource("", c.config.AllSettings(), c.config.ConfigFileUsed())
+
+	return nil
+}
+
+// clone copies the config, the template variables set on the copy do not leak into the original.
+func (c *Config) clone() (*Config, error) {
+	res := newConfig()
+	res.configPaths = c.configPaths
+
+	if err := res.config.MergeConfigMap(c.config.AllSettings()); err != nil {
+		return nil, err
+	}
+
+	maps.Copy(res.sources, c.sources)
+	maps.Copy(res.secretKeys, c.secretKeys)
+
+	// overrides take precedence over the environment, so they are kept in the override layer
+	for key, source := range c.sources {
+		if source == configSourceOverride {
+			res.config.Set(key, c.config.Get(key))
+		}
+	}
+
+	return res, nil
+}
+
+// Load merges the config sources on top of the clusterctl config in order, later sources override the earlier ones.
+//
+// Environment variables and Set values still take precedence over the loaded sources.
+//
+// Supported sources:
+//   - local file path or http:// or https:// URL;
+//   - local directory, the overlay files in it with the supported extensions are merged in the name order;
+//   - secret://namespace/name or configmap://namespace/name in the management cluster,
+//     the data keys with the supported extensions are merged as config files, other keys are config values.
+func (c *Config) Load(ctx context.Context, clientset kubernetes.Interface, sources ...string) error {
+	for _, source := range sources {
+		if err := c.load(ctx, clientset, source); err != nil {
+			return fmt.Errorf("failed to load config source %q: %w", source, err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) load(ctx context.Context, clientset kubernetes.Interface, source string) error {
+	switch {
+	case strings.HasPrefix(source, secretConfigScheme), strings.HasPrefix(source, configMapConfigScheme):
+		return c.loadObject(ctx, clientset, source)
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		data, err := fetchConfig(ctx, source)
+		if err != nil {
+			return err
+		}
+
+		return c.merge(data, "yaml", source)
+	}
+
+	info, err := os.Stat(source)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return c.mergeFile(source)
+	}
+
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || configFormat(entry.Name()) == "" {
+			continue
+		}
+
+		if err = c.mergeFile(filepath.Join(source, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadObject merges the Secret or the ConfigMap data.
+func (c *Config) loadObject(ctx context.Context, clientset kubernetes.Interface, source string) error {
+	if clientset == nil {
+		return fmt.Errorf("the management cluster is not connected")
+	}
+
+	secret := strings.HasPrefix(source, secretConfigScheme)
+
+	namespace, name, ok := strings.Cut(strings.TrimPrefix(strings.TrimPrefix(source, secretConfigScheme), configMapConfigScheme), "/")
+	if !ok || namespace == "" || name == "" {
+		return validationError(fmt.Errorf("expected %snamespace/name or %snamespace/name", secretConfigScheme, configMapConfigScheme))
+	}
+
+	data := map[string][]byte{}
+
+	if secret {
+		obj, err := clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		data = obj.Data
+	} else {
+		obj, err := clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		for key, value := range obj.Data {
+			data[key] = []byte(value)
+		}
+	}
+
+	values := map[string]any{}
+
+	for _, key := range slices.Sorted(maps.Keys(data)) {
+		if format := configFormat(key); format != "" {
+			settings, err := parseConfig(data[key], format)
+			if err != nil {
+				return fmt.Errorf("failed to parse %q: %w", key, err)
+			}
+
+			if err = c.mergeSettings(settings, source, secret); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		values[key] = string(data[key])
+	}
+
+	return c.mergeSettings(values, source, secret)
+}
+
+func (c *Config) mergeFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return c.merge(data, configFormat(path), path)
+}
+
+func (c *Config) merge(data []byte, format, source string) error {
+	settings, err := parseConfig(data, format)
+	if err != nil {
+		return err
+	}
+
+	return c.mergeSettings(settings, source, false)
+}
+
+func (c *Config) mergeSettings(settings map[string]any, source string, secret bool) error {
+	if err := c.config.MergeConfigMap(settings); err != nil {
+		return err
+	}
+
+	c.trackSource("", settings, source)
+
+	if secret {
+		c.markSecret("", settings)
+	}
+
+	return nil
+}
+
+// Override sets the values taking precedence over all config sources and the environment.
+func (c *Config) Override(values map[string]string) {
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		c.Set(key, values[key])
+	}
+}
+
+// View returns the effective config values sorted by key, the secret values are redacted.
+func (c *Config) View() []ConfigValue {
+	keys := c.config.AllKeys()
+	slices.Sort(keys)
+
+	view := make([]ConfigValue, 0, len(keys))
+
+	for _, key := range keys {
+		value := ConfigValue{
+			Key:    key,
+			Value:  c.config.Get(key),
+			Source: c.source(key),
+		}
+
+		if _, secret := c.secretKeys[key]; secret || sensitiveConfigKey(key) {
+			value.Value = redactedConfigValue
+			value.Redacted = true
+		}
+
+		view = append(view, value)
+	}
+
+	return view
+}
+
+// source returns the source of the effective value following the viper precedence.
+func (c *Config) source(key string) string {
+	if source := c.sources[key]; source == configSourceOverride {
+		return source
+	}
+
+	if _, ok := os.LookupEnv(strings.ToUpper(strings.ReplaceAll(key, "-", "_"))); ok {
+		return configSourceEnv
+	}
+
+	return c.sources[key]
+}
+
+// trackSource records the source of the flattened settings keys.
+func (c *Config) trackSource(prefix string, settings map[string]any, source string) {
+	for key, value := range settings {
+		if nested, ok := value.(map[string]any); ok {
+			c.trackSource(prefix+key+".", nested, source)
+
+			continue
+		}
+
+		c.sources[strings.ToLower(prefix+key)] = source
+		delete(c.secretKeys, strings.ToLower(prefix+key))
+	}
+}
+
+func (c *Config) markSecret(prefix string, settings map[string]any) {
+	for key, value := range settings {
+		if nested, ok := value.(map[string]any); ok {
+			c.markSecret(prefix+key+".", nested)
+
+			continue
+		}
+
+		c.secretKeys[strings.ToLower(prefix+key)] = struct{}{}
+	}
 }
 
 // Get implements config.Reader.
@@ -111,6 +376,9 @@ func (c *Config) Get(key string) (string, error) {
 // Set implements config.Reader.
 func (c *Config) Set(key, value string) {
 	c.config.Set(key, value)
+
+	c.sources[strings.ToLower(key)] = configSourceOverride
+	delete(c.secretKeys, strings.ToLower(key))
 }
 
 // UnmarshalKey implements config.Reader.
@@ -135,3 +403,63 @@ func (c *Config) checkDefaultConfig() bool {
 
 	return false
 }
+
+// configFormat returns the config format by the file extension, empty if it is not supported.
+func configFormat(path string) string {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+
+	if slices.Contains(viper.SupportedExts, ext) {
+		return ext
+	}
+
+	return ""
+}
+
+func parseConfig(data []byte, format string) (map[string]any, error) {
+	v := viper.New()
+	v.SetConfigType(format)
+
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+
+	return v.AllSettings(), nil
+}
+
+// sensitiveConfigKey returns true for the keys likely holding credentials.
+func sensitiveConfigKey(key string) bool {
+	key = strings.ToLower(key)
+
+	for _, word := range []string{"password", "secret", "token", "credential", "private"} {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fetchConfig(ctx context.Context, source string) ([]byte, error) {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the clusterctl config file from %s %w", source, err)
+	}
+
+	defer io.Copy(io.Discard, resp.Body) //nolint:errcheck
+	defer resp.Body.Close()              //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download the clusterctl config file from %s got %d", source, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
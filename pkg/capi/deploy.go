@@ -7,29 +7,57 @@ package capi
 import (
 	"context"
 	"fmt"
-	"log"
+	"maps"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/opencontainers/go-digest"
 	"github.com/siderolabs/go-retry/retry"
 	"github.com/siderolabs/talos/pkg/machinery/constants"
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	metavalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/siderolabs/capi-utils/pkg/capi/infrastructure"
+	capiconstants "github.com/siderolabs/capi-utils/pkg/constants"
 )
 
 // DeployOption defines a single CAPI cluster creation option.
 type DeployOption func(opts *DeployOptions) error
 
+// TopologyMachineDeployment defines a machine deployment of the ClusterClass based cluster.
+type TopologyMachineDeployment struct {
+	Variables map[string]any
+	Class     string
+	Name      string
+	Replicas  int64
+}
+
 // DeployOptions cluster deployment options.
 type DeployOptions struct {
 	providerOptions any
 
+	TopologyVariables          map[string]any
+	Labels                     map[string]string
+	Annotations                map[string]string
+	TopologyMachineDeployments []TopologyMachineDeployment
+	WorkerPools                []WorkerPool
+	Patches                    []Patch
+	ControlPlaneConfigPatches  []string
+	WorkerConfigPatches        []string
+
 	Provider          string
 	ProviderVersion   string
 	ClusterName       string
@@ -37,9 +65,13 @@ type DeployOptions struct {
 	TalosVersion      string
 	KubernetesVersion string
 	TemplateFile      string
+	TemplateChecksum  digest.Digest
+	TemplateCacheDir  string
+	ClusterClass      string
 	Template          []byte
 	ControlPlaneNodes int64
 	WorkerNodes       int64
+	TemplateCache     bool
 }
 
 // DefaultDeployOptions default deployment settings.
@@ -108,6 +140,8 @@ func WithKubernetesVersion(version string) DeployOption {
 }
 
 // WithTemplateFile load cluster template from the file.
+//
+// Besides the sources supported by clusterctl, OCI artifacts and git repositories are accepted, see FetchTemplate.
 func WithTemplateFile(path string) DeployOption {
 	return func(o *DeployOptions) error {
 		o.TemplateFile = path
@@ -116,6 +150,39 @@ func WithTemplateFile(path string) DeployOption {
 	}
 }
 
+// WithTemplateChecksum pins the cluster template to the checksum.
+//
+// The checksum is either a digest like sha256:<hex> or a bare sha256 hex string.
+func WithTemplateChecksum(checksum string) DeployOption {
+	return func(o *DeployOptions) error {
+		if !strings.Contains(checksum, ":") {
+			checksum = digest.SHA256.String() + ":" + checksum
+		}
+
+		d, err := digest.Parse(checksum)
+		if err != nil {
+			return fmt.Errorf("invalid template checksum: %w", err)
+		}
+
+		o.TemplateChecksum = d
+
+		return nil
+	}
+}
+
+// WithTemplateCache enables the local template cache in the directory.
+//
+// Templates pinned with WithTemplateChecksum are loaded from the cache without network access once cached.
+// Empty dir means the default location in the user cache directory.
+func WithTemplateCache(dir string) DeployOption {
+	return func(o *DeployOptions) error {
+		o.TemplateCache = true
+		o.TemplateCacheDir = dir
+
+		return nil
+	}
+}
+
 // WithTemplate loads cluster template from memory.
 func WithTemplate(data []byte) DeployOption {
 	return func(o *DeployOptions) error {
@@ -125,6 +192,95 @@ func WithTemplate(data []byte) DeployOption {
 	}
 }
 
+// WithClusterClass creates the cluster from the ClusterClass using managed topology instead of the template.
+func WithClusterClass(name string) DeployOption {
+	return func(o *DeployOptions) error {
+		o.ClusterClass = name
+
+		return nil
+	}
+}
+
+// WithTopologyVariables sets ClusterClass variables of the topology managed cluster.
+func WithTopologyVariables(vars map[string]any) DeployOption {
+	return func(o *DeployOptions) error {
+		o.TopologyVariables = vars
+
+		return nil
+	}
+}
+
+// WithTopologyMachineDeployment adds a worker machine deployment to the topology managed cluster.
+//
+// If no machine deployments are set, a single "md-0" deployment of the "default-worker" class with WorkerNodes replicas is created.
+func WithTopologyMachineDeployment(md TopologyMachineDeployment) DeployOption {
+	return func(o *DeployOptions) error {
+		if md.Class == "" || md.Name == "" {
+			return fmt.Errorf("topology machine deployment class and name are required")
+		}
+
+		o.TopologyMachineDeployments = append(o.TopologyMachineDeployments, md)
+
+		return nil
+	}
+}
+
+// WithWorkerPool adds a named worker pool deployed as a separate MachineDeployment.
+//
+// If any worker pools are set, WorkerNodes is ignored
+// and the cluster template should have a single MachineDeployment replaced by the pools.
+func WithWorkerPool(pool WorkerPool) DeployOption {
+	return func(o *DeployOptions) error {
+		if err := pool.validate(); err != nil {
+			return err
+		}
+
+		for _, p := range o.WorkerPools {
+			if p.Name == pool.Name {
+				return fmt.Errorf("duplicate worker pool %q", pool.Name)
+			}
+		}
+
+		o.WorkerPools = append(o.WorkerPools, pool)
+
+		return nil
+	}
+}
+
+// WithLabels adds labels to every object created by DeployCluster.
+func WithLabels(values map[string]string) DeployOption {
+	return func(o *DeployOptions) error {
+		if errs := metavalidation.ValidateLabels(values, field.NewPath("labels")); len(errs) > 0 {
+			return errs.ToAggregate()
+		}
+
+		if o.Labels == nil {
+			o.Labels = map[string]string{}
+		}
+
+		maps.Copy(o.Labels, values)
+
+		return nil
+	}
+}
+
+// WithAnnotations adds annotations to every object created by DeployCluster.
+func WithAnnotations(annotations map[string]string) DeployOption {
+	return func(o *DeployOptions) error {
+		if errs := apivalidation.ValidateAnnotations(annotations, field.NewPath("annotations")); len(errs) > 0 {
+			return errs.ToAggregate()
+		}
+
+		if o.Annotations == nil {
+			o.Annotations = map[string]string{}
+		}
+
+		maps.Copy(o.Annotations, annotations)
+
+		return nil
+	}
+}
+
 // WithDeployOptions sets deploy options as a struct.
 func WithDeployOptions(val *DeployOptions) DeployOption {
 	return func(o *DeployOptions) error {
@@ -153,47 +309,216 @@ func WithClusterNamespace(val string) DeployOption {
 }
 
 // DeployCluster creates a new cluster.
-//
-//nolint:gocognit
-func (clusterAPI *Manager) DeployCluster(ctx context.Context, clusterName string, setters ...DeployOption) (*Cluster, error) {
+func (clusterAPI *Manager) DeployCluster(ctx context.Context, clusterName string, setters ...DeployOption) (_ *Cluster, err error) {
+	metric := startOperationMetric(operationDeploy, "", clusterName)
+	defer func() { metric.finish(err) }()
+
+	ctx, span := startSpan(ctx, "DeployCluster", clusterNameKey.String(clusterName))
+	defer func() { endSpan(span, err) }()
+
+	renderCtx, renderSpan := startSpan(ctx, "DeployCluster/render")
+
+	options, provider, objs, err := clusterAPI.renderCluster(renderCtx, clusterName, setters...)
+
+	endSpan(renderSpan, err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	metric.provider = provider.Name()
+	metric.cluster = clusterLabel(clusterName, options.ClusterNamespace)
+
+	span.SetAttributes(clusterNamespaceKey.String(options.ClusterNamespace), providerKey.String(provider.Name()))
+
+	clusterAPI.logger.Info("creating cluster objects",
+		logKeyCluster, clusterName,
+		logKeyNamespace, options.ClusterNamespace,
+		logKeyProvider, provider.Name(),
+		logKeyPhase, "create",
+		"objects", len(objs),
+	)
+
+	createCtx, createSpan := startSpan(ctx, "DeployCluster/create", attribute.Int("capi.objects", len(objs)))
+
+	// events are recorded once the Cluster object exists
+	var clusterObj *unstructured.Unstructured
+
+	for i := range objs {
+		obj := &objs[i]
+
+		if err = clusterAPI.runtimeClient.Create(createCtx, obj); err != nil {
+			break
+		}
+
+		if obj.GroupVersionKind().GroupKind() == (schema.GroupKind{Group: "cluster.x-k8s.io", Kind: "Cluster"}) {
+			clusterObj = obj
+
+			clusterAPI.recordEvent(clusterObj, corev1.EventTypeNormal, EventReasonDeployStarted, "Deploying the cluster with the %s provider", provider.Name())
+		}
+	}
+
+	endSpan(createSpan, err)
+
+	if err != nil {
+		if clusterObj != nil {
+			clusterAPI.recordEvent(clusterObj, corev1.EventTypeWarning, EventReasonDeployFailed, "Failed to create the cluster objects: %s", err)
+		}
+
+		return nil, err
+	}
+
+	if clusterObj != nil {
+		clusterAPI.recordEvent(clusterObj, corev1.EventTypeNormal, EventReasonObjectsCreated, "Created %d cluster objects", len(objs))
+	}
+
+	waitCtx, waitSpan := startSpan(ctx, "DeployCluster/wait")
+	defer func() { endSpan(waitSpan, err) }()
+
+	deployedCluster, err := clusterAPI.NewCluster(waitCtx, options.ClusterName, options.ClusterNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	deployedCluster.logger().Info("waiting for the cluster to be ready", logKeyPhase, "wait")
+
+	if err = retry.Constant(30*time.Minute, retry.WithUnits(10*time.Second)).Retry(logRetries(deployedCluster.logger(), func() error {
+		return clusterAPI.CheckClusterReady(waitCtx, deployedCluster)
+	})); err != nil {
+		deployedCluster.recordEvent(corev1.EventTypeWarning, EventReasonDeployFailed, "Cluster is not ready: %s", err)
+
+		return nil, err
+	}
+
+	deployedCluster.recordEvent(corev1.EventTypeNormal, EventReasonDeployed, "Cluster is ready")
+
+	return deployedCluster, nil
+}
+
+// renderCluster generates the cluster objects ready to be created.
+func (clusterAPI *Manager) renderCluster(
+	ctx context.Context,
+	clusterName string,
+	setters ...DeployOption,
+) (*DeployOptions, infrastructure.Provider, []unstructured.Unstructured, error) {
+	options, provider, err := clusterAPI.deployOptions(ctx, clusterName, setters...)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var objs []unstructured.Unstructured
+
+	switch {
+	case options.ClusterClass != "":
+		if len(options.WorkerPools) > 0 {
+			return nil, nil, nil, validationError(fmt.Errorf("worker pools are not supported with ClusterClass, use topology machine deployments instead"))
+		}
+
+		if len(options.ControlPlaneConfigPatches) > 0 || len(options.WorkerConfigPatches) > 0 {
+			return nil, nil, nil, validationError(fmt.Errorf("talos config patches are not supported with ClusterClass, use topology variables instead"))
+		}
+
+		objs = []unstructured.Unstructured{clusterAPI.topologyCluster(options)}
+	case len(options.WorkerPools) > 0:
+		objs, err = clusterAPI.renderWorkerPools(ctx, provider, options)
+	default:
+		objs, err = clusterAPI.renderTemplate(ctx, provider, options)
+	}
+
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if err = applyTalosConfigPatches(objs, options); err != nil {
+		return nil, nil, nil, validationError(err)
+	}
+
+	if err = applyPatches(objs, options.Patches); err != nil {
+		return nil, nil, nil, validationError(err)
+	}
+
+	for i := range objs {
+		setMetadata(&objs[i], options)
+	}
+
+	return options, provider, objs, nil
+}
+
+// deployOptions applies the setters and picks the infrastructure provider for the deployment.
+func (clusterAPI *Manager) deployOptions(ctx context.Context, clusterName string, setters ...DeployOption) (*DeployOptions, infrastructure.Provider, error) {
 	if len(clusterAPI.providers) == 0 {
-		return nil, fmt.Errorf("no infrastructure providers are installed")
+		return nil, nil, fmt.Errorf("no infrastructure providers are installed: %w", ErrProviderNotInstalled)
 	}
 
 	options := DefaultDeployOptions()
 
 	for _, setter := range setters {
 		if err := setter(options); err != nil {
-			return nil, err
+			return nil, nil, validationError(err)
 		}
 	}
 
 	options.ClusterName = clusterName
 
-	var provider infrastructure.Provider
-
-	if options.Provider != "" {
-		for _, p := range clusterAPI.providers {
-			if p.Name() == options.Provider {
-				if options.ProviderVersion != "" && p.Version() != options.ProviderVersion {
-					continue
-				}
+	if err := options.resolveTemplate(ctx); err != nil {
+		return nil, nil, err
+	}
 
-				provider = p
+	if options.Provider == "" {
+		return options, clusterAPI.providers[0], nil
+	}
 
-				break
+	for _, p := range clusterAPI.providers {
+		if p.Name() == options.Provider {
+			if options.ProviderVersion != "" && p.Version() != options.ProviderVersion {
+				continue
 			}
-		}
 
-		if provider == nil {
-			return nil, fmt.Errorf("no provider with name %s is installed", options.Provider)
+			return options, p, nil
 		}
-	} else {
-		provider = clusterAPI.providers[0]
 	}
 
+	return nil, nil, fmt.Errorf("no provider with name %s is installed: %w", options.Provider, ErrProviderNotInstalled)
+}
+
+// renderTemplate generates cluster objects from the cluster template.
+func (clusterAPI *Manager) renderTemplate(ctx context.Context, provider infrastructure.Provider, options *DeployOptions) ([]unstructured.Unstructured, error) {
+	render, err := clusterAPI.newTemplateRender(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	templateOptions, vars, cleanup, err := render.templateOptions(clusterAPI, provider, options)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cleanup()
+
+	if err = render.checkTemplateVariables(provider, templateOptions, vars); err != nil {
+		return nil, err
+	}
+
+	template, err := provider.GetClusterTemplate(render.client, templateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return template.Objs(), nil
+}
+
+// templateOptions patches the config with the cluster variables and builds the template options.
+//
+// Returned variables are the provider cluster variables, cleanup removes the temporary template file.
+func (render *templateRender) templateOptions(
+	clusterAPI *Manager,
+	provider infrastructure.Provider,
+	options *DeployOptions,
+) (client.GetClusterTemplateOptions, infrastructure.Variables, func(), error) {
+	cleanup := func() {}
+
 	// set up env variables common for all providers
-	clusterAPI.patchConfig(infrastructure.Variables{
+	render.patchConfig(infrastructure.Variables{
 		"TALOS_VERSION":               options.TalosVersion,
 		"KUBERNETES_VERSION":          options.KubernetesVersion,
 		"CLUSTER_NAME":                options.ClusterName,
@@ -211,18 +536,23 @@ func (clusterAPI *Manager) DeployCluster(ctx context.Context, clusterName string
 	if options.Template != nil {
 		file, err := os.CreateTemp("", "clusterTemplate")
 		if err != nil {
-			log.Fatal(err)
+			return templateOptions, nil, cleanup, err
 		}
 
-		templateOptions.URLSource = &client.URLSourceOptions{
-			URL: file.Name(),
+		cleanup = func() {
+			file.Close()           //nolint:errcheck
+			os.Remove(file.Name()) //nolint:errcheck
 		}
 
 		if _, err = file.Write(options.Template); err != nil {
-			return nil, err
+			cleanup()
+
+			return templateOptions, nil, func() {}, err
 		}
 
-		defer file.Close() //nolint:errcheck
+		templateOptions.URLSource = &client.URLSourceOptions{
+			URL: file.Name(),
+		}
 	} else if options.TemplateFile != "" {
 		templateOptions.URLSource = &client.URLSourceOptions{
 			URL: options.TemplateFile,
@@ -231,38 +561,203 @@ func (clusterAPI *Manager) DeployCluster(ctx context.Context, clusterName string
 
 	vars, err := provider.ClusterVars(options.providerOptions)
 	if err != nil {
-		return nil, err
+		cleanup()
+
+		return templateOptions, nil, func() {}, err
 	}
 
-	clusterAPI.patchConfig(vars)
+	render.patchConfig(vars)
 
-	template, err := provider.GetClusterTemplate(clusterAPI.client, templateOptions)
-	if err != nil {
+	return templateOptions, vars, cleanup, nil
+}
+
+// topologyCluster generates the Cluster object using managed topology.
+func (clusterAPI *Manager) topologyCluster(options *DeployOptions) unstructured.Unstructured {
+	machineDeployments := options.TopologyMachineDeployments
+	if len(machineDeployments) == 0 {
+		machineDeployments = []TopologyMachineDeployment{
+			{
+				Class:    "default-worker",
+				Name:     "md-0",
+				Replicas: options.WorkerNodes,
+			},
+		}
+	}
+
+	workers := make([]any, 0, len(machineDeployments))
+
+	for _, md := range machineDeployments {
+		workers = append(workers, map[string]any{
+			"class":    md.Class,
+			"name":     md.Name,
+			"replicas": md.Replicas,
+			"variables": map[string]any{
+				"overrides": topologyVariables(md.Variables),
+			},
+		})
+	}
+
+	topology := map[string]any{
+		"class":   options.ClusterClass,
+		"version": capiKubernetesVersion(options.KubernetesVersion),
+		"controlPlane": map[string]any{
+			"replicas": options.ControlPlaneNodes,
+		},
+		"workers": map[string]any{
+			"machineDeployments": workers,
+		},
+	}
+
+	if len(options.TopologyVariables) > 0 {
+		topology["variables"] = topologyVariables(options.TopologyVariables)
+	}
+
+	cluster := unstructured.Unstructured{
+		Object: map[string]any{
+			"spec": map[string]any{
+				"topology": topology,
+			},
+		},
+	}
+
+	cluster.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "cluster.x-k8s.io",
+		Kind:    "Cluster",
+		Version: clusterAPI.version,
+	})
+	cluster.SetName(options.ClusterName)
+	cluster.SetNamespace(options.ClusterNamespace)
+
+	return cluster
+}
+
+func topologyVariables(vars map[string]any) []any {
+	res := make([]any, 0, len(vars))
+
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		res = append(res, map[string]any{
+			"name":  name,
+			"value": vars[name],
+		})
+	}
+
+	return res
+}
+
+// setMetadata adds the common labels, annotations and the managed-by label to the object.
+func setMetadata(obj *unstructured.Unstructured, options *DeployOptions) {
+	objLabels := obj.GetLabels()
+	if objLabels == nil {
+		objLabels = map[string]string{}
+	}
+
+	maps.Copy(objLabels, options.Labels)
+	objLabels[capiconstants.ManagedByLabel] = capiconstants.ManagedByValue
+
+	obj.SetLabels(objLabels)
+
+	if len(options.Annotations) == 0 {
+		return
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	maps.Copy(annotations, options.Annotations)
+
+	obj.SetAnnotations(annotations)
+}
+
+// ManagedSelector returns the label selector matching the clusters created by DeployCluster.
+//
+// Extra labels narrow the selection down.
+func ManagedSelector(extra map[string]string) labels.Selector {
+	set := labels.Set{
+		capiconstants.ManagedByLabel: capiconstants.ManagedByValue,
+	}
+
+	maps.Copy(set, extra)
+
+	return labels.SelectorFromSet(set)
+}
+
+// ListClusters gets the Cluster objects matching the selector from the namespace.
+//
+// Empty namespace means all namespaces, nil selector matches everything.
+func (clusterAPI *Manager) ListClusters(ctx context.Context, namespace string, selector labels.Selector) (*unstructured.UnstructuredList, error) {
+	var clusters unstructured.UnstructuredList
+
+	clusters.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "cluster.x-k8s.io",
+		Kind:    "ClusterList",
+		Version: clusterAPI.version,
+	})
+
+	opts := []runtimeclient.ListOption{
+		runtimeclient.InNamespace(namespace),
+	}
+
+	if selector != nil {
+		opts = append(opts, runtimeclient.MatchingLabelsSelector{Selector: selector})
+	}
+
+	if err := clusterAPI.runtimeClient.List(ctx, &clusters, opts...); err != nil {
 		return nil, err
 	}
 
-	for _, obj := range template.Objs() {
-		if err = clusterAPI.runtimeClient.Create(ctx, &obj); err != nil {
-			return nil, err
-		}
+	return &clusters, nil
+}
+
+// DestroyOptions cluster deletion options.
+type DestroyOptions struct {
+	Selector labels.Selector
+}
+
+// DestroyOption defines a single CAPI cluster deletion option.
+type DestroyOption func(opts *DestroyOptions)
+
+// WithDestroySelector refuses to delete the cluster unless its labels match the selector.
+func WithDestroySelector(selector labels.Selector) DestroyOption {
+	return func(opts *DestroyOptions) {
+		opts.Selector = selector
 	}
+}
 
-	deployedCluster, err := clusterAPI.NewCluster(ctx, options.ClusterName, options.ClusterNamespace)
+// DestroyClusters deletes all clusters matching the selector in the namespace.
+//
+// Empty namespace means all namespaces.
+func (clusterAPI *Manager) DestroyClusters(ctx context.Context, namespace string, selector labels.Selector) ([]string, error) {
+	clusters, err := clusterAPI.ListClusters(ctx, namespace, selector)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = retry.Constant(30*time.Minute, retry.WithUnits(10*time.Second), retry.WithErrorLogging(true)).Retry(func() error {
-		return clusterAPI.CheckClusterReady(ctx, deployedCluster)
-	}); err != nil {
-		return nil, err
+	deleted := make([]string, 0, len(clusters.Items))
+
+	for _, cluster := range clusters.Items {
+		if err = clusterAPI.DestroyCluster(ctx, cluster.GetName(), cluster.GetNamespace(), WithDestroySelector(selector)); err != nil {
+			return deleted, err
+		}
+
+		deleted = append(deleted, fmt.Sprintf("%s/%s", cluster.GetNamespace(), cluster.GetName()))
 	}
 
-	return deployedCluster, nil
+	return deleted, nil
 }
 
 // DestroyCluster deletes cluster.
-func (clusterAPI *Manager) DestroyCluster(ctx context.Context, name, namespace string) error {
+func (clusterAPI *Manager) DestroyCluster(ctx context.Context, name, namespace string, setters ...DestroyOption) (err error) {
+	metric := startOperationMetric(operationDestroy, "", clusterLabel(name, namespace))
+	defer func() { metric.finish(err) }()
+
+	var options DestroyOptions
+
+	for _, setter := range setters {
+		setter(&options)
+	}
+
 	cluster := &unstructured.Unstructured{}
 	cluster.SetName(name)
 	cluster.SetNamespace(namespace)
@@ -272,6 +767,21 @@ func (clusterAPI *Manager) DestroyCluster(ctx context.Context, name, namespace s
 		Version: clusterAPI.version,
 	})
 
+	// the event is bound to the fetched object UID to be shown by kubectl describe
+	if options.Selector != nil || clusterAPI.recorder != nil {
+		if err := clusterAPI.runtimeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, cluster); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	if options.Selector != nil && !options.Selector.Matches(labels.Set(cluster.GetLabels())) {
+		return fmt.Errorf("cluster %s/%s does not match the selector %q", namespace, name, options.Selector)
+	}
+
 	if err := clusterAPI.runtimeClient.Delete(ctx, cluster); err != nil {
 		if errors.IsNotFound(err) {
 			return nil
@@ -280,7 +790,13 @@ func (clusterAPI *Manager) DestroyCluster(ctx context.Context, name, namespace s
 		return err
 	}
 
-	return retry.Constant(30*time.Minute, retry.WithUnits(10*time.Second), retry.WithErrorLogging(true)).Retry(func() error {
+	clusterAPI.recordEvent(cluster, corev1.EventTypeNormal, EventReasonDestroyRequested, "Cluster deletion requested")
+
+	logger := clusterAPI.logger.With(logKeyCluster, name, logKeyNamespace, namespace)
+
+	logger.Info("waiting for the cluster to be deleted", logKeyPhase, "destroy")
+
+	return retry.Constant(30*time.Minute, retry.WithUnits(10*time.Second)).Retry(logRetries(logger, func() error {
 		err := clusterAPI.runtimeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, cluster)
 		if err != nil {
 			if errors.IsNotFound(err) {
@@ -291,5 +807,5 @@ func (clusterAPI *Manager) DestroyCluster(ctx context.Context, name, namespace s
 		}
 
 		return retry.ExpectedError(fmt.Errorf("cluster is being deleted"))
-	})
+	}))
 }
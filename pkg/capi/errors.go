@@ -0,0 +1,70 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/siderolabs/go-retry/retry"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ErrProviderNotInstalled is returned when the infrastructure provider required for the deployment is not installed.
+var ErrProviderNotInstalled = errors.New("infrastructure provider is not installed")
+
+// ValidationError is returned for invalid options and inputs.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func validationError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &ValidationError{Err: err}
+}
+
+// ErrorCategory is a coarse error classification for the machine-readable output.
+type ErrorCategory string
+
+// Error categories.
+const (
+	ErrorCategoryTimeout         ErrorCategory = "timeout"
+	ErrorCategoryNotFound        ErrorCategory = "not_found"
+	ErrorCategoryProviderMissing ErrorCategory = "provider_missing"
+	ErrorCategoryValidation      ErrorCategory = "validation"
+	ErrorCategoryInternal        ErrorCategory = "internal"
+)
+
+// Category classifies the error.
+func Category(err error) ErrorCategory {
+	var (
+		validationErr *ValidationError
+		missingVars   *MissingVariablesError
+	)
+
+	switch {
+	case retry.IsTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return ErrorCategoryTimeout
+	case errors.Is(err, ErrProviderNotInstalled):
+		return ErrorCategoryProviderMissing
+	case errors.As(err, &validationErr), errors.As(err, &missingVars):
+		return ErrorCategoryValidation
+	case apierrors.IsNotFound(err):
+		return ErrorCategoryNotFound
+	default:
+		return ErrorCategoryInternal
+	}
+}
@@ -0,0 +1,132 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/reference"
+)
+
+// EventComponent is the source component of the recorded events.
+const EventComponent = "capi-utils"
+
+// Reasons of the events recorded on the Cluster objects.
+const (
+	EventReasonDeployStarted     = "DeployStarted"
+	EventReasonObjectsCreated    = "ObjectsCreated"
+	EventReasonDeployed          = "Deployed"
+	EventReasonDeployFailed      = "DeployFailed"
+	EventReasonScaling           = "Scaling"
+	EventReasonScaled            = "Scaled"
+	EventReasonScaleFailed       = "ScaleFailed"
+	EventReasonHealthCheckPassed = "HealthCheckPassed"
+	EventReasonHealthCheckFailed = "HealthCheckFailed"
+	EventReasonDestroyRequested  = "DestroyRequested"
+)
+
+// eventTimeout bounds writing a single event to the management cluster.
+const eventTimeout = 10 * time.Second
+
+// setupEvents picks the event recorder, the events are not recorded if neither EventRecorder nor RecordEvents is set.
+func (clusterAPI *Manager) setupEvents() {
+	if clusterAPI.options.EventRecorder != nil {
+		clusterAPI.recorder = clusterAPI.options.EventRecorder
+
+		return
+	}
+
+	if !clusterAPI.options.RecordEvents {
+		return
+	}
+
+	clusterAPI.recorder = &eventRecorder{
+		clientset: clusterAPI.clientset,
+		logger:    clusterAPI.logger,
+	}
+}
+
+// eventRecorder writes the events to the management cluster synchronously,
+// so the events are not lost when the CLI exits right after the operation.
+type eventRecorder struct {
+	clientset kubernetes.Interface
+	logger    *slog.Logger
+}
+
+// Event implements record.EventRecorder.
+func (r *eventRecorder) Event(object runtime.Object, eventType, reason, message string) {
+	r.record(object, nil, eventType, reason, message)
+}
+
+// Eventf implements record.EventRecorder.
+func (r *eventRecorder) Eventf(object runtime.Object, eventType, reason, messageFmt string, args ...any) {
+	r.record(object, nil, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf implements record.EventRecorder.
+func (r *eventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventType, reason, messageFmt string, args ...any) {
+	r.record(object, annotations, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// record creates the event, failures are logged as the events are not essential for the operations.
+func (r *eventRecorder) record(object runtime.Object, annotations map[string]string, eventType, reason, message string) {
+	objectRef, err := reference.GetReference(clientgoscheme.Scheme, object)
+	if err != nil {
+		r.logger.Warn("failed to get the event object reference", "reason", reason, "error", err)
+
+		return
+	}
+
+	namespace := objectRef.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	now := metav1.Now()
+
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s.%x", objectRef.Name, now.UnixNano()),
+			Namespace:   namespace,
+			Annotations: annotations,
+		},
+		InvolvedObject:      *objectRef,
+		Reason:              reason,
+		Message:             message,
+		FirstTimestamp:      now,
+		LastTimestamp:       now,
+		Count:               1,
+		Type:                eventType,
+		Source:              corev1.EventSource{Component: EventComponent},
+		ReportingController: EventComponent,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), eventTimeout)
+	defer cancel()
+
+	if _, err = r.clientset.CoreV1().Events(namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		r.logger.Warn("failed to record the event", "object", objectRef.Name, "reason", reason, "error", err)
+	}
+}
+
+func (clusterAPI *Manager) recordEvent(object runtime.Object, eventType, reason, messageFmt string, args ...any) {
+	if clusterAPI.recorder == nil {
+		return
+	}
+
+	clusterAPI.recorder.Eventf(object, eventType, reason, messageFmt, args...)
+}
+
+func (cluster *Cluster) recordEvent(eventType, reason, messageFmt string, args ...any) {
+	cluster.manager.recordEvent(&cluster.cluster, eventType, reason, messageFmt, args...)
+}
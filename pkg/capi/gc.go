@@ -0,0 +1,156 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/siderolabs/capi-utils/pkg/constants"
+)
+
+// WithTTL marks the cluster as expired after the ttl, see Manager.GarbageCollect.
+func WithTTL(ttl time.Duration) DeployOption {
+	return WithExpiry(time.Now().Add(ttl))
+}
+
+// WithExpiry marks the cluster as expired after the deadline, see Manager.GarbageCollect.
+func WithExpiry(deadline time.Time) DeployOption {
+	return WithAnnotations(map[string]string{
+		constants.ExpiresAtAnnotation: deadline.UTC().Format(time.RFC3339),
+	})
+}
+
+// GCOptions garbage collection options.
+type GCOptions struct {
+	Namespace   string
+	Concurrency int
+	DryRun      bool
+}
+
+// GCOption defines a single garbage collection option.
+type GCOption func(opts *GCOptions)
+
+// WithGCNamespace limits garbage collection to the namespace, all namespaces are checked by default.
+func WithGCNamespace(namespace string) GCOption {
+	return func(opts *GCOptions) {
+		opts.Namespace = namespace
+	}
+}
+
+// WithGCConcurrency sets the number of clusters destroyed in parallel.
+func WithGCConcurrency(concurrency int) GCOption {
+	return func(opts *GCOptions) {
+		opts.Concurrency = concurrency
+	}
+}
+
+// WithGCDryRun only reports expired clusters without destroying them.
+func WithGCDryRun(dryRun bool) GCOption {
+	return func(opts *GCOptions) {
+		opts.DryRun = dryRun
+	}
+}
+
+// GCReport describes the garbage collection results.
+//
+// Clusters are identified as namespace/name.
+type GCReport struct {
+	// Failed clusters could not be destroyed or have malformed expiry annotation.
+	Failed map[string]error
+	// Expired clusters are found expired, in dry run mode they are not destroyed.
+	Expired []string
+	// Removed clusters are destroyed.
+	Removed []string
+}
+
+// GarbageCollect destroys the clusters created by DeployCluster with the expiry deadline before now.
+//
+// Clusters are destroyed in parallel, failures do not stop the collection and are reported.
+func (clusterAPI *Manager) GarbageCollect(ctx context.Context, now time.Time, setters ...GCOption) (*GCReport, error) {
+	options := GCOptions{
+		Concurrency: 4,
+	}
+
+	for _, setter := range setters {
+		setter(&options)
+	}
+
+	if options.Concurrency < 1 {
+		return nil, validationError(fmt.Errorf("garbage collection concurrency should be positive, got %d", options.Concurrency))
+	}
+
+	clusters, err := clusterAPI.ListClusters(ctx, options.Namespace, ManagedSelector(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &GCReport{
+		Failed: map[string]error{},
+	}
+
+	var (
+		mu sync.Mutex
+		eg errgroup.Group
+	)
+
+	eg.SetLimit(options.Concurrency)
+
+	for _, cluster := range clusters.Items {
+		value, ok := cluster.GetAnnotations()[constants.ExpiresAtAnnotation]
+		if !ok {
+			continue
+		}
+
+		id := fmt.Sprintf("%s/%s", cluster.GetNamespace(), cluster.GetName())
+
+		deadline, e := time.Parse(time.RFC3339, value)
+		if e != nil {
+			// destroy goroutines write the failures concurrently
+			mu.Lock()
+			report.Failed[id] = fmt.Errorf("malformed %s annotation: %w", constants.ExpiresAtAnnotation, e)
+			mu.Unlock()
+
+			continue
+		}
+
+		if deadline.After(now) {
+			continue
+		}
+
+		report.Expired = append(report.Expired, id)
+
+		if options.DryRun {
+			continue
+		}
+
+		eg.Go(func() error {
+			e := clusterAPI.DestroyCluster(ctx, cluster.GetName(), cluster.GetNamespace(), WithDestroySelector(ManagedSelector(nil)))
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if e != nil {
+				report.Failed[id] = e
+			} else {
+				report.Removed = append(report.Removed, id)
+			}
+
+			return nil
+		})
+	}
+
+	eg.Wait() //nolint:errcheck
+
+	slices.Sort(report.Expired)
+	slices.Sort(report.Removed)
+
+	return report, nil
+}
@@ -7,6 +7,7 @@ package infrastructure
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strconv"
 	"time"
 
@@ -35,6 +36,7 @@ func NewAWSProvider(version, providerNS, watchingNS string) (*AWSProvider, error
 
 // AWSProvider infrastructure provider.
 type AWSProvider struct {
+	Logger                *slog.Logger
 	B64EncodedCredentials string
 	ProviderVersion       string
 	ProviderNS            string
@@ -55,22 +57,22 @@ type AWSSetupOptions struct {
 
 // AWSDeployOptions defines provider specific settings for cluster deployment.
 type AWSDeployOptions struct {
-	ControlPlaneMachineType   string
-	ControlPlaneIAMProfile    string
-	ControlPlaneAMIID         string
-	ControlPlaneADDLSecGroups string
-	NodeMachineType           string
-	NodeIAMProfile            string
-	NodeAMIID                 string
-	NodeADDLSecGroups         string
-	Region                    string
-	SSHKeyName                string
-	VPCID                     string
-	Subnet                    string
-	CloudProviderVersion      string
-	CalicoVersion             string
-	ControlPlaneVolSize       int64
-	NodeVolSize               int64
+	ControlPlaneMachineType   string `json:"controlPlaneMachineType,omitempty"`
+	ControlPlaneIAMProfile    string `json:"controlPlaneIAMProfile,omitempty"`
+	ControlPlaneAMIID         string `json:"controlPlaneAMIID,omitempty"`
+	ControlPlaneADDLSecGroups string `json:"controlPlaneADDLSecGroups,omitempty"`
+	NodeMachineType           string `json:"nodeMachineType,omitempty"`
+	NodeIAMProfile            string `json:"nodeIAMProfile,omitempty"`
+	NodeAMIID                 string `json:"nodeAMIID,omitempty"`
+	NodeADDLSecGroups         string `json:"nodeADDLSecGroups,omitempty"`
+	Region                    string `json:"region,omitempty"`
+	SSHKeyName                string `json:"sshKeyName,omitempty"`
+	VPCID                     string `json:"vpcID,omitempty"`
+	Subnet                    string `json:"subnet,omitempty"`
+	CloudProviderVersion      string `json:"cloudProviderVersion,omitempty"`
+	CalicoVersion             string `json:"calicoVersion,omitempty"`
+	ControlPlaneVolSize       int64  `json:"controlPlaneVolSize,omitempty"`
+	NodeVolSize               int64  `json:"nodeVolSize,omitempty"`
 }
 
 // NewAWSDeployOptions returns default deploy options for the AWS infra provider.
@@ -192,23 +194,44 @@ func (s *AWSProvider) GetClusterTemplate(client client.Client, opts client.GetCl
 
 // WaitReady implements Provider interface.
 func (s *AWSProvider) WaitReady(ctx context.Context, clientset *kubernetes.Clientset) error {
-	return retry.Constant(10*time.Minute, retry.WithUnits(10*time.Second), retry.WithErrorLogging(true)).Retry(func() error {
-		if _, err := clientset.CoreV1().Namespaces().Get(ctx, s.Namespace(), metav1.GetOptions{}); err != nil {
-			return retry.ExpectedError(err)
-		}
+	var last string
 
-		var (
-			err        error
-			deployment *v1.Deployment
-		)
-		if deployment, err = clientset.AppsV1().Deployments(s.Namespace()).Get(ctx, "capa-controller-manager", metav1.GetOptions{}); err != nil {
-			return retry.ExpectedError(err)
-		}
+	return retry.Constant(10*time.Minute, retry.WithUnits(10*time.Second)).Retry(func() error {
+		err := s.checkReady(ctx, clientset)
+		if err != nil && err.Error() != last {
+			last = err.Error()
 
-		if deployment.Status.ReadyReplicas != deployment.Status.Replicas || deployment.Status.ReadyReplicas == 0 {
-			return retry.ExpectedError(fmt.Errorf("%d of %d replicas ready", deployment.Status.ReadyReplicas, deployment.Status.Replicas))
+			s.logger().Info("retrying", "provider", s.Name(), "error", err)
 		}
 
-		return nil
+		return err
 	})
 }
+
+func (s *AWSProvider) logger() *slog.Logger {
+	if s.Logger == nil {
+		return slog.Default()
+	}
+
+	return s.Logger
+}
+
+func (s *AWSProvider) checkReady(ctx context.Context, clientset *kubernetes.Clientset) error {
+	if _, err := clientset.CoreV1().Namespaces().Get(ctx, s.Namespace(), metav1.GetOptions{}); err != nil {
+		return retry.ExpectedError(err)
+	}
+
+	var (
+		err        error
+		deployment *v1.Deployment
+	)
+	if deployment, err = clientset.AppsV1().Deployments(s.Namespace()).Get(ctx, "capa-controller-manager", metav1.GetOptions{}); err != nil {
+		return retry.ExpectedError(err)
+	}
+
+	if deployment.Status.ReadyReplicas != deployment.Status.Replicas || deployment.Status.ReadyReplicas == 0 {
+		return retry.ExpectedError(fmt.Errorf("%d of %d replicas ready", deployment.Status.ReadyReplicas, deployment.Status.Replicas))
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ package infrastructure
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"k8s.io/client-go/kubernetes"
@@ -35,6 +36,7 @@ type Provider interface {
 
 // ProviderOptions is the functional options struct.
 type ProviderOptions struct {
+	Logger     *slog.Logger
 	ProviderNS string
 	WatchingNS string
 }
@@ -56,6 +58,13 @@ func WithWatchingNS(ns string) ProviderOption {
 	}
 }
 
+// WithLogger sets the logger of the provider.
+func WithLogger(logger *slog.Logger) ProviderOption {
+	return func(opts *ProviderOptions) {
+		opts.Logger = logger
+	}
+}
+
 // NewProvider creates a new provider from a specified type.
 func NewProvider(providerType string, opts ...ProviderOption) (Provider, error) {
 	// Handle any functional options
@@ -75,11 +84,18 @@ func NewProvider(providerType string, opts ...ProviderOption) (Provider, error)
 	}
 
 	if parts[0] == constants.AWSProviderName {
-		return NewAWSProvider(
+		provider, err := NewAWSProvider(
 			version,
 			providerOpts.ProviderNS,
 			providerOpts.WatchingNS,
 		)
+		if err != nil {
+			return nil, err
+		}
+
+		provider.Logger = providerOpts.Logger
+
+		return provider, nil
 	}
 
 	return nil, fmt.Errorf("unknown infrastructure provider type %s", parts[0])
@@ -0,0 +1,58 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/siderolabs/go-retry/retry"
+)
+
+// Structured logging keys.
+const (
+	logKeyCluster   = "cluster"
+	logKeyNamespace = "namespace"
+	logKeyProvider  = "provider"
+	logKeyPhase     = "phase"
+)
+
+func (cluster *Cluster) logger() *slog.Logger {
+	return cluster.manager.logger.With(logKeyCluster, cluster.name, logKeyNamespace, cluster.namespace)
+}
+
+// logRetries logs the new errors of the retried function.
+//
+// It replaces retry.WithErrorLogging which writes to the standard logger.
+func logRetries(logger *slog.Logger, f retry.RetryableFunc) retry.RetryableFunc {
+	var last string
+
+	return func() error {
+		err := f()
+		if err != nil && err.Error() != last {
+			last = err.Error()
+
+			logger.Info("retrying", "error", err)
+		}
+
+		return err
+	}
+}
+
+// logRetriesWithContext is logRetries for retry.RetryableFuncWithContext.
+func logRetriesWithContext(logger *slog.Logger, f retry.RetryableFuncWithContext) retry.RetryableFuncWithContext {
+	var last string
+
+	return func(ctx context.Context) error {
+		err := f(ctx)
+		if err != nil && err.Error() != last {
+			last = err.Error()
+
+			logger.Info("retrying", "error", err)
+		}
+
+		return err
+	}
+}
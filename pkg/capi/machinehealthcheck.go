@@ -0,0 +1,435 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/siderolabs/go-retry/retry"
+	talosclient "github.com/siderolabs/talos/pkg/machinery/client"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// UnhealthyCondition is a node condition which marks the machine unhealthy after the timeout.
+type UnhealthyCondition struct {
+	Type    corev1.NodeConditionType
+	Status  corev1.ConditionStatus
+	Timeout time.Duration
+}
+
+// MachineHealthCheckOptions defines MachineHealthCheck settings.
+type MachineHealthCheckOptions struct {
+	Name                string
+	MaxUnhealthy        string
+	UnhealthyConditions []UnhealthyCondition
+	NodeStartupTimeout  time.Duration
+}
+
+// MachineHealthCheckOption optional MachineHealthCheck parameter setter.
+type MachineHealthCheckOption func(*MachineHealthCheckOptions)
+
+// WithMachineHealthCheckName overrides the generated MachineHealthCheck name.
+func WithMachineHealthCheckName(name string) MachineHealthCheckOption {
+	return func(opts *MachineHealthCheckOptions) {
+		opts.Name = name
+	}
+}
+
+// WithUnhealthyCondition adds a node condition which marks the machine unhealthy.
+//
+// Setting any condition replaces the default ones.
+func WithUnhealthyCondition(conditionType corev1.NodeConditionType, status corev1.ConditionStatus, timeout time.Duration) MachineHealthCheckOption {
+	return func(opts *MachineHealthCheckOptions) {
+		opts.UnhealthyConditions = append(opts.UnhealthyConditions, UnhealthyCondition{
+			Type:    conditionType,
+			Status:  status,
+			Timeout: timeout,
+		})
+	}
+}
+
+// WithMaxUnhealthy sets the max number or percentage of unhealthy machines which can be remediated.
+func WithMaxUnhealthy(value string) MachineHealthCheckOption {
+	return func(opts *MachineHealthCheckOptions) {
+		opts.MaxUnhealthy = value
+	}
+}
+
+// WithNodeStartupTimeout sets the time to wait for the node to join the cluster.
+func WithNodeStartupTimeout(timeout time.Duration) MachineHealthCheckOption {
+	return func(opts *MachineHealthCheckOptions) {
+		opts.NodeStartupTimeout = timeout
+	}
+}
+
+// CreateMachineHealthCheck creates a MachineHealthCheck for the nodes group.
+func (cluster *Cluster) CreateMachineHealthCheck(ctx context.Context, nodes NodeGroup, setters ...MachineHealthCheckOption) (*unstructured.Unstructured, error) {
+	var (
+		opts     MachineHealthCheckOptions
+		selector map[string]any
+		suffix   string
+	)
+
+	for _, s := range setters {
+		s(&opts)
+	}
+
+	switch nodes {
+	case ControlPlaneNodes:
+		suffix = "control-plane"
+		selector = map[string]any{
+			"matchLabels": map[string]any{
+				clusterv1.MachineControlPlaneLabel: "",
+			},
+		}
+	case WorkerNodes:
+		suffix = "workers"
+		selector = map[string]any{
+			"matchExpressions": []any{
+				map[string]any{
+					"key":      clusterv1.MachineDeploymentNameLabel,
+					"operator": string(metav1.LabelSelectorOpExists),
+				},
+			},
+		}
+	case MachinePoolNodes:
+		suffix = "machine-pools"
+		selector = map[string]any{
+			"matchExpressions": []any{
+				map[string]any{
+					"key":      clusterv1.MachinePoolNameLabel,
+					"operator": string(metav1.LabelSelectorOpExists),
+				},
+			},
+		}
+	default:
+		return nil, fmt.Errorf("unknown nodes group %d", nodes)
+	}
+
+	if opts.Name == "" {
+		opts.Name = fmt.Sprintf("%s-%s", cluster.name, suffix)
+	}
+
+	if opts.MaxUnhealthy == "" {
+		opts.MaxUnhealthy = "100%"
+	}
+
+	if opts.NodeStartupTimeout == 0 {
+		opts.NodeStartupTimeout = 10 * time.Minute
+	}
+
+	if len(opts.UnhealthyConditions) == 0 {
+		opts.UnhealthyConditions = []UnhealthyCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionUnknown, Timeout: 5 * time.Minute},
+			{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Timeout: 5 * time.Minute},
+		}
+	}
+
+	unhealthyConditions := make([]any, 0, len(opts.UnhealthyConditions))
+
+	for _, cond := range opts.UnhealthyConditions {
+		unhealthyConditions = append(unhealthyConditions, map[string]any{
+			"type":    string(cond.Type),
+			"status":  string(cond.Status),
+			"timeout": cond.Timeout.String(),
+		})
+	}
+
+	healthCheck := &unstructured.Unstructured{
+		Object: map[string]any{
+			"spec": map[string]any{
+				"clusterName":         cluster.name,
+				"maxUnhealthy":        opts.MaxUnhealthy,
+				"nodeStartupTimeout":  opts.NodeStartupTimeout.String(),
+				"selector":            selector,
+				"unhealthyConditions": unhealthyConditions,
+			},
+		},
+	}
+
+	healthCheck.SetGroupVersionKind(cluster.machineHealthCheckGVK())
+	healthCheck.SetName(opts.Name)
+	healthCheck.SetNamespace(cluster.namespace)
+	healthCheck.SetLabels(map[string]string{
+		clusterv1.ClusterNameLabel: cluster.name,
+	})
+
+	if err := cluster.manager.runtimeClient.Create(ctx, healthCheck); err != nil {
+		return nil, err
+	}
+
+	return healthCheck, nil
+}
+
+// MachineHealthChecks gets MachineHealthCheck list of the cluster from the management cluster.
+func (cluster *Cluster) MachineHealthChecks(ctx context.Context) (*unstructured.UnstructuredList, error) {
+	var healthChecks unstructured.UnstructuredList
+
+	healthChecks.SetGroupVersionKind(cluster.machineHealthCheckGVK())
+
+	if err := cluster.manager.runtimeClient.List(ctx, &healthChecks,
+		runtimeclient.InNamespace(cluster.namespace),
+		runtimeclient.MatchingLabels{clusterv1.ClusterNameLabel: cluster.name},
+	); err != nil {
+		return nil, err
+	}
+
+	return &healthChecks, nil
+}
+
+// DeleteMachineHealthCheck deletes MachineHealthCheck by name.
+func (cluster *Cluster) DeleteMachineHealthCheck(ctx context.Context, name string) error {
+	healthCheck := &unstructured.Unstructured{}
+	healthCheck.SetGroupVersionKind(cluster.machineHealthCheckGVK())
+	healthCheck.SetName(name)
+	healthCheck.SetNamespace(cluster.namespace)
+
+	if err := cluster.manager.runtimeClient.Delete(ctx, healthCheck); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// RemediationResult describes the machine killed by CheckRemediation and its replacement.
+type RemediationResult struct {
+	Machine      string
+	Node         string
+	Replacements []string
+}
+
+// CheckRemediation shuts down a node from the nodes group and waits for CAPI to replace its machine.
+//
+// A validation error is returned if no MachineHealthCheck selects the machine picked for the shutdown.
+//
+//nolint:gocognit,gocyclo,cyclop
+func (cluster *Cluster) CheckRemediation(ctx context.Context, nodes NodeGroup) (*RemediationResult, error) {
+	machines, err := cluster.nodeGroupMachines(ctx, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	before := make([]string, 0, len(machines))
+
+	var (
+		victim    *unstructured.Unstructured
+		address   string
+		addresses []any
+	)
+
+	for i := range machines {
+		machine := &machines[i]
+
+		before = append(before, machine.GetName())
+
+		if victim != nil {
+			continue
+		}
+
+		addresses, _, err = unstructured.NestedSlice(machine.Object, "status", "addresses")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, a := range addresses {
+			addr, ok := a.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			if addr["type"] == string(clusterv1.MachineInternalIP) {
+				address, _ = addr["address"].(string) //nolint:errcheck
+
+				victim = machine
+
+				break
+			}
+		}
+	}
+
+	if victim == nil {
+		return nil, fmt.Errorf("failed to find a machine with an internal IP address")
+	}
+
+	// without a MachineHealthCheck the machine is never remediated and the wait would only time out
+	healthChecks, err := cluster.selectingMachineHealthChecks(ctx, victim)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(healthChecks) == 0 {
+		return nil, validationError(fmt.Errorf("no MachineHealthCheck selects the machine %s", victim.GetName()))
+	}
+
+	result := &RemediationResult{
+		Machine: victim.GetName(),
+	}
+
+	result.Node, _, err = unstructured.NestedString(victim.Object, "status", "nodeRef", "name")
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := cluster.TalosClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = client.Shutdown(talosclient.WithNodes(ctx, address), talosclient.WithShutdownForce(true)); err != nil {
+		return nil, err
+	}
+
+	err = retry.Constant(30*time.Minute, retry.WithUnits(10*time.Second)).Retry(logRetries(cluster.logger(), func() error {
+		machine := &unstructured.Unstructured{}
+		machine.SetGroupVersionKind(victim.GroupVersionKind())
+
+		if e := cluster.manager.runtimeClient.Get(ctx, types.NamespacedName{Name: victim.GetName(), Namespace: victim.GetNamespace()}, machine); e == nil {
+			return retry.ExpectedErrorf("machine %s is not remediated yet", victim.GetName())
+		} else if !errors.IsNotFound(e) {
+			return e
+		}
+
+		current, e := cluster.nodeGroupMachines(ctx, nodes)
+		if e != nil {
+			return e
+		}
+
+		if len(current) < len(before) {
+			return retry.ExpectedErrorf("expected %d machines, current machines count: %d", len(before), len(current))
+		}
+
+		if e = cluster.manager.CheckClusterReady(ctx, cluster); e != nil {
+			return e
+		}
+
+		result.Replacements = result.Replacements[:0]
+
+		for _, m := range current {
+			if !slices.Contains(before, m.GetName()) {
+				result.Replacements = append(result.Replacements, m.GetName())
+			}
+		}
+
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return result, cluster.Sync(ctx)
+}
+
+// selectingMachineHealthChecks returns the names of the cluster MachineHealthChecks selecting the machine.
+func (cluster *Cluster) selectingMachineHealthChecks(ctx context.Context, machine *unstructured.Unstructured) ([]string, error) {
+	var healthChecks unstructured.UnstructuredList
+
+	healthChecks.SetGroupVersionKind(cluster.machineHealthCheckGVK())
+
+	// the MachineHealthChecks created elsewhere may lack the cluster name label
+	if err := cluster.manager.runtimeClient.List(ctx, &healthChecks, runtimeclient.InNamespace(cluster.namespace)); err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, healthCheck := range healthChecks.Items {
+		clusterName, _, err := unstructured.NestedString(healthCheck.Object, "spec", "clusterName")
+		if err != nil {
+			return nil, err
+		}
+
+		if clusterName != cluster.name {
+			continue
+		}
+
+		selectorMap, _, err := unstructured.NestedMap(healthCheck.Object, "spec", "selector")
+		if err != nil {
+			return nil, err
+		}
+
+		var labelSelector metav1.LabelSelector
+
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(selectorMap, &labelSelector); err != nil {
+			return nil, err
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		if selector.Matches(labels.Set(machine.GetLabels())) {
+			names = append(names, healthCheck.GetName())
+		}
+	}
+
+	return names, nil
+}
+
+// nodeGroupMachines returns the machines of the nodes group which have nodes attached.
+func (cluster *Cluster) nodeGroupMachines(ctx context.Context, nodes NodeGroup) ([]unstructured.Unstructured, error) {
+	machines, err := cluster.Machines(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]unstructured.Unstructured, 0, len(machines.Items))
+
+	var groupLabel string
+
+	switch nodes {
+	case ControlPlaneNodes:
+		groupLabel = clusterv1.MachineControlPlaneLabel
+	case WorkerNodes:
+		groupLabel = clusterv1.MachineDeploymentNameLabel
+	case MachinePoolNodes:
+		groupLabel = clusterv1.MachinePoolNameLabel
+	default:
+		return nil, fmt.Errorf("unknown nodes group %d", nodes)
+	}
+
+	for _, machine := range machines.Items {
+		if _, ok := machine.GetLabels()[groupLabel]; !ok {
+			continue
+		}
+
+		if _, found, _ := unstructured.NestedMap(machine.Object, "status", "nodeRef"); !found { //nolint:errcheck
+			continue
+		}
+
+		res = append(res, machine)
+	}
+
+	slices.SortFunc(res, func(a, b unstructured.Unstructured) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+
+	return res, nil
+}
+
+func (cluster *Cluster) machineHealthCheckGVK() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Version: cluster.manager.version,
+		Group:   "cluster.x-k8s.io",
+		Kind:    "MachineHealthCheck",
+	}
+}
@@ -0,0 +1,159 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/siderolabs/go-retry/retry"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const metricsNamespace = "capi_utils"
+
+// Operation label values of the lifecycle metrics.
+const (
+	operationInstall    = "install"
+	operationDeploy     = "deploy"
+	operationScale      = "scale"
+	operationDestroy    = "destroy"
+	operationHealth     = "health"
+	operationCheckReady = "check_ready"
+)
+
+var (
+	operationLabels = []string{"operation", "outcome", "provider", "cluster"}
+
+	operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of the cluster lifecycle operations.",
+		Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600, 900, 1200, 1800, 2700, 3600},
+	}, operationLabels)
+
+	operationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "operations_total",
+		Help:      "Number of the cluster lifecycle operations.",
+	}, operationLabels)
+
+	managedClustersDesc = prometheus.NewDesc(
+		metricsNamespace+"_managed_clusters",
+		"Number of the clusters created by capi-utils by phase.",
+		[]string{"namespace", "phase"},
+		nil,
+	)
+)
+
+// operationMetric measures a single lifecycle operation.
+type operationMetric struct {
+	start     time.Time
+	operation string
+	provider  string
+	cluster   string
+}
+
+func startOperationMetric(operation, provider, cluster string) *operationMetric {
+	return &operationMetric{
+		start:     time.Now(),
+		operation: operation,
+		provider:  provider,
+		cluster:   cluster,
+	}
+}
+
+func (m *operationMetric) finish(err error) {
+	labels := []string{m.operation, operationOutcome(err), m.provider, m.cluster}
+
+	operationDuration.WithLabelValues(labels...).Observe(time.Since(m.start).Seconds())
+	operationsTotal.WithLabelValues(labels...).Inc()
+}
+
+func operationOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case retry.IsTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "error"
+	}
+}
+
+// clusterLabel is the cluster label value of the lifecycle metrics.
+func clusterLabel(name, namespace string) string {
+	return namespace + "/" + name
+}
+
+// providerLabel derives the provider label value from the infrastructure cluster kind, e.g. AWSCluster is aws.
+func (cluster *Cluster) providerLabel() string {
+	kind, _, _ := unstructured.NestedString(cluster.cluster.Object, "spec", "infrastructureRef", "kind") //nolint:errcheck
+
+	return strings.ToLower(strings.TrimSuffix(kind, "Cluster"))
+}
+
+// MetricsCollector exposes the lifecycle operation metrics and the number of the managed clusters by phase.
+type MetricsCollector struct {
+	manager *Manager
+	// Timeout limits the time of listing the clusters on each scrape.
+	Timeout time.Duration
+}
+
+// NewMetricsCollector creates the Prometheus collector for the manager.
+//
+// Operation metrics are process wide, so only one collector should be registered.
+func NewMetricsCollector(manager *Manager) *MetricsCollector {
+	return &MetricsCollector{
+		manager: manager,
+		Timeout: 10 * time.Second,
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *MetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	operationDuration.Describe(ch)
+	operationsTotal.Describe(ch)
+
+	ch <- managedClustersDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *MetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	operationDuration.Collect(ch)
+	operationsTotal.Collect(ch)
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	clusters, err := c.manager.ListClusters(ctx, "", ManagedSelector(nil))
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(managedClustersDesc, err)
+
+		return
+	}
+
+	type key struct {
+		namespace string
+		phase     string
+	}
+
+	counts := map[key]int{}
+
+	for _, cluster := range clusters.Items {
+		phase, _, _ := unstructured.NestedString(cluster.Object, "status", "phase") //nolint:errcheck
+
+		counts[key{namespace: cluster.GetNamespace(), phase: phase}]++
+	}
+
+	for k, count := range counts {
+		ch <- prometheus.MustNewConstMetric(managedClustersDesc, prometheus.GaugeValue, float64(count), k.namespace, k.phase)
+	}
+}
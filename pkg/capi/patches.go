@@ -0,0 +1,369 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	yamlutil "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// PatchType defines how the patch is applied.
+type PatchType int
+
+const (
+	// JSONMergePatch is a partial object merged into the target as a JSON merge patch (RFC 7386):
+	// maps are merged, lists are replaced.
+	//
+	// Cluster objects are custom resources without patch strategy metadata,
+	// so the kustomize strategic merge patches are applied this way.
+	JSONMergePatch PatchType = iota
+	// JSON6902Patch is a list of JSON patch (RFC 6902) operations.
+	JSON6902Patch
+)
+
+// PatchTarget selects the objects to patch, empty fields match any value.
+type PatchTarget struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Patch defines a single cluster objects patch.
+type Patch struct {
+	Target PatchTarget
+	Patch  []byte
+	Type   PatchType
+}
+
+// WithPatches adds patches applied to the cluster objects before they are created.
+//
+// Patches are applied in order, every patch should match at least one object.
+func WithPatches(patches ...Patch) DeployOption {
+	return func(o *DeployOptions) error {
+		o.Patches = append(o.Patches, patches...)
+
+		return nil
+	}
+}
+
+// LoadPatches reads patches from the directory.
+//
+// If the directory has kustomization.yaml, patches are taken from its patches list
+// where each entry has either path or inline patch and an optional target,
+// and from the patchesStrategicMerge and patchesJson6902 lists, other kustomization fields are rejected.
+// Otherwise each .yaml/.yml/.json file is read as a JSON merge patch targeted
+// by its apiVersion, kind and metadata.name.
+func LoadPatches(dir string) ([]Patch, error) {
+	for _, name := range []string{"kustomization.yaml", "kustomization.yml", "Kustomization"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return loadKustomizationPatches(dir, data)
+		}
+
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []Patch
+
+	for _, entry := range entries {
+		if entry.IsDir() || !slices.Contains([]string{".yaml", ".yml", ".json"}, filepath.Ext(entry.Name())) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		patches, err := mergePatches(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load patch %s: %w", entry.Name(), err)
+		}
+
+		res = append(res, patches...)
+	}
+
+	return res, nil
+}
+
+// kustomizationPatch is an entry of the kustomization patches or patchesJson6902 lists.
+type kustomizationPatch struct {
+	Target *PatchTarget `json:"target,omitempty"`
+	Path   string       `json:"path,omitempty"`
+	Patch  string       `json:"patch,omitempty"`
+}
+
+func loadKustomizationPatches(dir string, data []byte) ([]Patch, error) {
+	var kustomization struct {
+		APIVersion            string               `json:"apiVersion,omitempty"`
+		Kind                  string               `json:"kind,omitempty"`
+		Patches               []kustomizationPatch `json:"patches,omitempty"`
+		PatchesStrategicMerge []string             `json:"patchesStrategicMerge,omitempty"`
+		PatchesJSON6902       []kustomizationPatch `json:"patchesJson6902,omitempty"`
+	}
+
+	if err := yaml.UnmarshalStrict(data, &kustomization); err != nil {
+		return nil, fmt.Errorf("failed to decode kustomization: %w", err)
+	}
+
+	var res []Patch
+
+	for i, entry := range kustomization.Patches {
+		patches, err := entry.load(dir, fmt.Sprintf("patches[%d]", i), false)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, patches...)
+	}
+
+	for i, entry := range kustomization.PatchesStrategicMerge {
+		patch := []byte(entry)
+
+		// the entry is either a file path or an inline patch
+		if !strings.Contains(entry, "\n") {
+			var err error
+
+			if patch, err = os.ReadFile(filepath.Join(dir, entry)); err != nil {
+				return nil, err
+			}
+		}
+
+		patches, err := mergePatches(patch)
+		if err != nil {
+			return nil, fmt.Errorf("kustomization patchesStrategicMerge[%d]: %w", i, err)
+		}
+
+		res = append(res, patches...)
+	}
+
+	for i, entry := range kustomization.PatchesJSON6902 {
+		patches, err := entry.load(dir, fmt.Sprintf("patchesJson6902[%d]", i), true)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, patches...)
+	}
+
+	return res, nil
+}
+
+// load reads the kustomization patch entry, the patch type is detected unless JSON6902 is required.
+func (entry *kustomizationPatch) load(dir, field string, json6902 bool) ([]Patch, error) {
+	if (entry.Path == "") == (entry.Patch == "") {
+		return nil, fmt.Errorf("kustomization %s: exactly one of path or patch should be set", field)
+	}
+
+	patch := []byte(entry.Patch)
+
+	if entry.Path != "" {
+		var err error
+
+		if patch, err = os.ReadFile(filepath.Join(dir, entry.Path)); err != nil {
+			return nil, err
+		}
+	}
+
+	var operations []any
+
+	// JSON6902 patch is a list of operations, anything else is a JSON merge patch
+	if json6902 || yaml.Unmarshal(patch, &operations) == nil && operations != nil {
+		if entry.Target == nil {
+			return nil, fmt.Errorf("kustomization %s: JSON6902 patch requires a target", field)
+		}
+
+		jsonPatch, err := yaml.YAMLToJSON(patch)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err = jsonpatch.DecodePatch(jsonPatch); err != nil {
+			return nil, fmt.Errorf("kustomization %s: %w", field, err)
+		}
+
+		return []Patch{
+			{
+				Target: *entry.Target,
+				Patch:  jsonPatch,
+				Type:   JSON6902Patch,
+			},
+		}, nil
+	}
+
+	patches, err := mergePatches(patch)
+	if err != nil {
+		return nil, fmt.Errorf("kustomization %s: %w", field, err)
+	}
+
+	if entry.Target != nil {
+		for j := range patches {
+			patches[j].Target = *entry.Target
+		}
+	}
+
+	return patches, nil
+}
+
+// mergePatches decodes multi document YAML into JSON merge patches targeted by the objects identity.
+func mergePatches(data []byte) ([]Patch, error) {
+	var res []Patch
+
+	decoder := yamlutil.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+
+	for {
+		var obj unstructured.Unstructured
+
+		if err := decoder.Decode(&obj.Object); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		if len(obj.Object) == 0 {
+			continue
+		}
+
+		patch, err := obj.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+
+		gvk := obj.GroupVersionKind()
+
+		res = append(res, Patch{
+			Target: PatchTarget{
+				Group:     gvk.Group,
+				Version:   gvk.Version,
+				Kind:      gvk.Kind,
+				Name:      obj.GetName(),
+				Namespace: obj.GetNamespace(),
+			},
+			Patch: patch,
+			Type:  JSONMergePatch,
+		})
+	}
+
+	return res, nil
+}
+
+func (target *PatchTarget) matches(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+
+	for _, check := range [][2]string{
+		{target.Group, gvk.Group},
+		{target.Version, gvk.Version},
+		{target.Kind, gvk.Kind},
+		{target.Name, obj.GetName()},
+		{target.Namespace, obj.GetNamespace()},
+	} {
+		if check[0] != "" && check[0] != check[1] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (target *PatchTarget) String() string {
+	var parts []string
+
+	gvk := schema.GroupVersionKind{Group: target.Group, Version: target.Version, Kind: target.Kind}
+	if !gvk.Empty() {
+		parts = append(parts, gvk.String())
+	}
+
+	if target.Namespace != "" {
+		parts = append(parts, "namespace="+target.Namespace)
+	}
+
+	if target.Name != "" {
+		parts = append(parts, "name="+target.Name)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// applyPatches patches the objects in place.
+func applyPatches(objs []unstructured.Unstructured, patches []Patch) error {
+	for i, patch := range patches {
+		var (
+			jsonPatch jsonpatch.Patch
+			err       error
+		)
+
+		switch patch.Type {
+		case JSONMergePatch:
+		case JSON6902Patch:
+			if jsonPatch, err = jsonpatch.DecodePatch(patch.Patch); err != nil {
+				return fmt.Errorf("patch %d: %w", i, err)
+			}
+		default:
+			return fmt.Errorf("patch %d: unknown patch type %d", i, patch.Type)
+		}
+
+		var matched bool
+
+		for j := range objs {
+			obj := &objs[j]
+
+			if !patch.Target.matches(obj) {
+				continue
+			}
+
+			matched = true
+
+			original, err := obj.MarshalJSON()
+			if err != nil {
+				return err
+			}
+
+			var patched []byte
+
+			if patch.Type == JSON6902Patch {
+				patched, err = jsonPatch.Apply(original)
+			} else {
+				patched, err = jsonpatch.MergePatch(original, patch.Patch)
+			}
+
+			if err != nil {
+				return fmt.Errorf("patch %d: failed to patch %s %s: %w", i, obj.GetKind(), obj.GetName(), err)
+			}
+
+			if err = obj.UnmarshalJSON(patched); err != nil {
+				return err
+			}
+		}
+
+		if !matched {
+			return fmt.Errorf("patch %d: no objects match the target %s", i, &patch.Target)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/siderolabs/go-retry/retry"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/siderolabs/capi-utils/pkg/constants"
+)
+
+// Pause stops reconciliation of the cluster and all its owned objects.
+//
+// It sets spec.paused on the Cluster, the paused annotation on the owned objects
+// and waits until CAPI controllers report them as paused.
+// Pause is a no-op if the Cluster is already paused.
+func (cluster *Cluster) Pause(ctx context.Context) error {
+	return cluster.setPaused(ctx, true)
+}
+
+// Resume restarts reconciliation of the cluster previously stopped by Pause.
+//
+// The Cluster and the objects paused by someone else are left paused,
+// Resume is a no-op if the Cluster was paused by someone else.
+func (cluster *Cluster) Resume(ctx context.Context) error {
+	return cluster.setPaused(ctx, false)
+}
+
+// Paused returns true if the cluster reconciliation is paused.
+func (cluster *Cluster) Paused(ctx context.Context) (bool, error) {
+	if err := cluster.sync(ctx); err != nil {
+		return false, err
+	}
+
+	paused, _, err := unstructured.NestedBool(cluster.cluster.Object, "spec", "paused")
+
+	return paused, err
+}
+
+func (cluster *Cluster) setPaused(ctx context.Context, paused bool) error {
+	if err := cluster.sync(ctx); err != nil {
+		return err
+	}
+
+	specPaused, _, err := unstructured.NestedBool(cluster.cluster.Object, "spec", "paused")
+	if err != nil {
+		return err
+	}
+
+	annotations := cluster.cluster.GetAnnotations()
+	_, marked := annotations[constants.PausedByAnnotation]
+
+	switch {
+	case paused && specPaused:
+		return nil
+	case !paused && specPaused && !marked:
+		// the Cluster paused by someone else is left paused, so are all its objects
+		cluster.logger().Info("cluster is paused by someone else, leaving it paused")
+
+		return nil
+	case paused:
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		annotations[constants.PausedByAnnotation] = ""
+	default:
+		delete(annotations, constants.PausedByAnnotation)
+	}
+
+	cluster.cluster.SetAnnotations(annotations)
+
+	if err = unstructured.SetNestedField(cluster.cluster.Object, paused, "spec", "paused"); err != nil {
+		return err
+	}
+
+	if err = cluster.manager.runtimeClient.Update(ctx, &cluster.cluster); err != nil {
+		return err
+	}
+
+	objects, err := cluster.ownedObjects(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		annotations := object.GetAnnotations()
+
+		_, annotated := annotations[clusterv1.PausedAnnotation]
+		_, marked := annotations[constants.PausedByAnnotation]
+
+		// the objects paused by someone else are left paused
+		switch {
+		case paused && !annotated:
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+
+			annotations[clusterv1.PausedAnnotation] = ""
+			annotations[constants.PausedByAnnotation] = ""
+		case !paused && marked:
+			delete(annotations, clusterv1.PausedAnnotation)
+			delete(annotations, constants.PausedByAnnotation)
+		default:
+			continue
+		}
+
+		object.SetAnnotations(annotations)
+
+		if err = cluster.manager.runtimeClient.Update(ctx, object); err != nil {
+			return err
+		}
+	}
+
+	return retry.Constant(5*time.Minute, retry.WithUnits(5*time.Second)).Retry(logRetries(cluster.logger(), func() error {
+		if e := cluster.sync(ctx); e != nil {
+			return e
+		}
+
+		if e := checkPausedCondition(&cluster.cluster, paused); e != nil {
+			return e
+		}
+
+		owned, e := cluster.ownedObjects(ctx)
+		if e != nil {
+			return e
+		}
+
+		for _, object := range owned {
+			if e = checkPausedCondition(object, paused); e != nil {
+				return e
+			}
+		}
+
+		return nil
+	}))
+}
+
+// ownedObjects returns the control plane, infrastructure cluster, machine deployments, machine pools and machines of the cluster.
+func (cluster *Cluster) ownedObjects(ctx context.Context) ([]*unstructured.Unstructured, error) {
+	var res []*unstructured.Unstructured
+
+	for _, field := range []string{"controlPlaneRef", "infrastructureRef"} {
+		objectRef, err := getRef(cluster.cluster.Object, "spec", field)
+		if err != nil {
+			return nil, err
+		}
+
+		object := &unstructured.Unstructured{}
+		object.SetGroupVersionKind(objectRef.gvk)
+
+		if err = cluster.manager.runtimeClient.Get(ctx, objectRef.NamespacedName, object); err != nil {
+			return nil, err
+		}
+
+		res = append(res, object)
+	}
+
+	for _, kind := range []string{"MachineDeployment", "MachineSet", "MachinePool", "Machine"} {
+		var list unstructured.UnstructuredList
+
+		list.SetGroupVersionKind(
+			schema.GroupVersionKind{
+				Version: cluster.manager.version,
+				Group:   "cluster.x-k8s.io",
+				Kind:    kind,
+			},
+		)
+
+		if err := cluster.manager.runtimeClient.List(ctx, &list,
+			runtimeclient.InNamespace(cluster.namespace),
+			runtimeclient.MatchingLabels{clusterv1.ClusterNameLabel: cluster.name},
+		); err != nil {
+			// MachinePool CRDs are not installed if the feature is disabled
+			if meta.IsNoMatchError(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		for i := range list.Items {
+			res = append(res, &list.Items[i])
+		}
+	}
+
+	return res, nil
+}
+
+// checkPausedCondition verifies the v1beta2 Paused condition of the object.
+//
+// Objects which do not report v1beta2 conditions are checked by spec.paused and the paused annotation.
+func checkPausedCondition(object *unstructured.Unstructured, paused bool) error {
+	annotations := object.GetAnnotations()
+
+	_, annotated := annotations[clusterv1.PausedAnnotation]
+	_, marked := annotations[constants.PausedByAnnotation]
+
+	specPaused, _, err := unstructured.NestedBool(object.Object, "spec", "paused")
+	if err != nil {
+		return err
+	}
+
+	// the objects paused by someone else stay paused on resume
+	if (annotated || specPaused) && !marked {
+		paused = true
+	}
+
+	status, found, err := findCondition(object.Object, clusterv1.PausedV1Beta2Condition, "status", "v1beta2", "conditions")
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		if actual := annotated || specPaused; actual != paused {
+			return retry.ExpectedError(fmt.Errorf("%s %s paused is %t", object.GetKind(), object.GetName(), actual))
+		}
+
+		return nil
+	}
+
+	expected := corev1.ConditionFalse
+	if paused {
+		expected = corev1.ConditionTrue
+	}
+
+	if corev1.ConditionStatus(status) != expected {
+		return retry.ExpectedError(fmt.Errorf("%s %s condition %s is %s", object.GetKind(), object.GetName(), clusterv1.PausedV1Beta2Condition, status))
+	}
+
+	return nil
+}
+
+// findCondition returns the status of the condition with the given type from the conditions list located at fields.
+func findCondition(object map[string]any, conditionType string, fields ...string) (string, bool, error) {
+	conditions, found, err := unstructured.NestedSlice(object, fields...)
+	if err != nil || !found {
+		return "", false, err
+	}
+
+	var t, status string
+
+	for _, cond := range conditions {
+		c, ok := cond.(map[string]any)
+		if !ok {
+			return "", false, fmt.Errorf("failed to convert condition to map[string]interface{}")
+		}
+
+		if t, _, err = unstructured.NestedString(c, "type"); err != nil {
+			return "", false, err
+		}
+
+		if t != conditionType {
+			continue
+		}
+
+		if status, _, err = unstructured.NestedString(c, "status"); err != nil {
+			return "", false, err
+		}
+
+		return status, true, nil
+	}
+
+	return "", false, nil
+}
@@ -0,0 +1,442 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/siderolabs/capi-utils/pkg/constants"
+)
+
+// PoolProfile defines a kind of pooled clusters.
+type PoolProfile struct {
+	// Reset cleans up the cluster when it is released with ReleaseReset.
+	Reset func(ctx context.Context, cluster *Cluster) error
+	Name  string
+	// Options are used to deploy the profile clusters.
+	Options []DeployOption
+	// Size is the number of the idle clusters to keep ready.
+	Size int
+}
+
+// PoolOptions cluster pool options.
+type PoolOptions struct {
+	Holder        string
+	LeaseDuration time.Duration
+	PollInterval  time.Duration
+	DeployTimeout time.Duration
+}
+
+// PoolOption defines a single cluster pool option.
+type PoolOption func(opts *PoolOptions)
+
+// WithPoolHolder sets the identity of the lease holder, defaults to hostname and pid.
+func WithPoolHolder(holder string) PoolOption {
+	return func(opts *PoolOptions) {
+		opts.Holder = holder
+	}
+}
+
+// WithPoolLeaseDuration sets the duration of the lease, expired leases are reclaimed by Replenish.
+func WithPoolLeaseDuration(duration time.Duration) PoolOption {
+	return func(opts *PoolOptions) {
+		opts.LeaseDuration = duration
+	}
+}
+
+// WithPoolPollInterval sets how often Acquire checks for the idle clusters.
+func WithPoolPollInterval(interval time.Duration) PoolOption {
+	return func(opts *PoolOptions) {
+		opts.PollInterval = interval
+	}
+}
+
+// WithPoolDeployTimeout sets how long the cluster may stay not ready after it was created,
+// Replenish destroys the clusters which were not ready in time.
+func WithPoolDeployTimeout(timeout time.Duration) PoolOption {
+	return func(opts *PoolOptions) {
+		opts.DeployTimeout = timeout
+	}
+}
+
+// Pool keeps ready clusters and leases them.
+//
+// Pool state is stored in the Cluster objects labels and annotations,
+// so several pools in different processes can share the clusters.
+type Pool struct {
+	manager   *Manager
+	profiles  map[string]PoolProfile
+	namespace string
+	options   PoolOptions
+}
+
+// Lease is a cluster acquired from the pool.
+type Lease struct {
+	ExpiresAt time.Time
+	Cluster   *Cluster
+	Profile   string
+	Holder    string
+}
+
+// ReleaseMode defines what happens to the released cluster.
+type ReleaseMode int
+
+const (
+	// ReleaseReturn returns the cluster to the pool as is.
+	ReleaseReturn ReleaseMode = iota
+	// ReleaseReset runs the profile Reset hook and returns the cluster to the pool,
+	// the cluster is destroyed if the hook is not set or fails, the hook error is returned then.
+	ReleaseReset
+	// ReleaseDestroy destroys the cluster.
+	ReleaseDestroy
+)
+
+// NewPool creates a cluster pool in the namespace.
+func (clusterAPI *Manager) NewPool(namespace string, profiles []PoolProfile, setters ...PoolOption) (*Pool, error) {
+	options := PoolOptions{
+		LeaseDuration: time.Hour,
+		PollInterval:  10 * time.Second,
+		DeployTimeout: time.Hour,
+	}
+
+	for _, setter := range setters {
+		setter(&options)
+	}
+
+	if options.Holder == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+
+		options.Holder = fmt.Sprintf("%s-%d", hostname, os.Getpid())
+	}
+
+	pool := &Pool{
+		manager:   clusterAPI,
+		namespace: namespace,
+		profiles:  make(map[string]PoolProfile, len(profiles)),
+		options:   options,
+	}
+
+	for _, profile := range profiles {
+		if errs := validation.IsDNS1123Label(profile.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid pool profile name %q: %v", profile.Name, errs)
+		}
+
+		if _, ok := pool.profiles[profile.Name]; ok {
+			return nil, fmt.Errorf("duplicate pool profile %q", profile.Name)
+		}
+
+		pool.profiles[profile.Name] = profile
+	}
+
+	return pool, nil
+}
+
+// Acquire leases an idle ready cluster of the profile.
+//
+// It blocks until a cluster is available or the context is canceled.
+func (pool *Pool) Acquire(ctx context.Context, profile string) (*Lease, error) {
+	if _, ok := pool.profiles[profile]; !ok {
+		return nil, fmt.Errorf("unknown pool profile %q", profile)
+	}
+
+	ticker := time.NewTicker(pool.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		lease, err := pool.tryAcquire(ctx, profile)
+		if err != nil || lease != nil {
+			return lease, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("no idle clusters of the profile %q: %w", profile, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (pool *Pool) tryAcquire(ctx context.Context, profile string) (*Lease, error) {
+	clusters, err := pool.clusters(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		annotations := cluster.GetAnnotations()
+
+		if _, ready := annotations[constants.PoolReadyAnnotation]; !ready {
+			continue
+		}
+
+		if _, leased := annotations[constants.LeaseHolderAnnotation]; leased {
+			continue
+		}
+
+		expiresAt := time.Now().Add(pool.options.LeaseDuration)
+
+		annotations[constants.LeaseHolderAnnotation] = pool.options.Holder
+		annotations[constants.LeaseExpiresAtAnnotation] = expiresAt.UTC().Format(time.RFC3339)
+
+		cluster.SetAnnotations(annotations)
+
+		// the update fails on conflict if another holder leased the cluster first
+		if err = pool.manager.runtimeClient.Update(ctx, cluster); err != nil {
+			if apierrors.IsConflict(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		leased, err := pool.manager.NewCluster(ctx, cluster.GetName(), cluster.GetNamespace())
+		if err != nil {
+			return nil, err
+		}
+
+		return &Lease{
+			Cluster:   leased,
+			Profile:   profile,
+			Holder:    pool.options.Holder,
+			ExpiresAt: expiresAt,
+		}, nil
+	}
+
+	return nil, nil
+}
+
+// Renew extends the lease by the lease duration.
+func (pool *Pool) Renew(ctx context.Context, lease *Lease) error {
+	expiresAt := time.Now().Add(pool.options.LeaseDuration)
+
+	if err := pool.updateLease(ctx, lease, func(annotations map[string]string) {
+		annotations[constants.LeaseExpiresAtAnnotation] = expiresAt.UTC().Format(time.RFC3339)
+	}); err != nil {
+		return err
+	}
+
+	lease.ExpiresAt = expiresAt
+
+	return nil
+}
+
+// Release gives the leased cluster back to the pool.
+func (pool *Pool) Release(ctx context.Context, lease *Lease, mode ReleaseMode) error {
+	switch mode {
+	case ReleaseReturn:
+	case ReleaseReset:
+		reset := pool.profiles[lease.Profile].Reset
+		if reset == nil {
+			return pool.manager.DestroyCluster(ctx, lease.Cluster.Name(), lease.Cluster.Namespace())
+		}
+
+		if err := reset(ctx, lease.Cluster); err != nil {
+			return errors.Join(
+				fmt.Errorf("failed to reset the cluster %q: %w", lease.Cluster.Name(), err),
+				pool.manager.DestroyCluster(ctx, lease.Cluster.Name(), lease.Cluster.Namespace()),
+			)
+		}
+	case ReleaseDestroy:
+		return pool.manager.DestroyCluster(ctx, lease.Cluster.Name(), lease.Cluster.Namespace())
+	default:
+		return fmt.Errorf("unknown release mode %d", mode)
+	}
+
+	return pool.updateLease(ctx, lease, func(annotations map[string]string) {
+		delete(annotations, constants.LeaseHolderAnnotation)
+		delete(annotations, constants.LeaseExpiresAtAnnotation)
+	})
+}
+
+func (pool *Pool) updateLease(ctx context.Context, lease *Lease, update func(annotations map[string]string)) error {
+	if err := lease.Cluster.sync(ctx); err != nil {
+		return err
+	}
+
+	cluster := &lease.Cluster.cluster
+	annotations := cluster.GetAnnotations()
+
+	if holder := annotations[constants.LeaseHolderAnnotation]; holder != lease.Holder {
+		return fmt.Errorf("cluster %s is leased by %q, not %q", cluster.GetName(), holder, lease.Holder)
+	}
+
+	update(annotations)
+	cluster.SetAnnotations(annotations)
+
+	return pool.manager.runtimeClient.Update(ctx, cluster)
+}
+
+// Replenish destroys the clusters with expired leases and the clusters not ready after the deploy timeout,
+// and deploys the clusters missing in the pool.
+//
+// Only ready clusters which are not leased are counted as idle.
+// Clusters are deployed in parallel, the call blocks until all of them are ready.
+//
+//nolint:gocognit
+func (pool *Pool) Replenish(ctx context.Context) error {
+	var (
+		eg   errgroup.Group
+		mu   sync.Mutex
+		errs []error
+	)
+
+	collect := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		errs = append(errs, err)
+	}
+
+	for _, profile := range pool.profiles {
+		clusters, err := pool.clusters(ctx, profile.Name)
+		if err != nil {
+			return err
+		}
+
+		// deploying clusters are not idle yet, but they are not replaced until the deploy timeout
+		var idle, deploying int
+
+		for _, cluster := range clusters.Items {
+			if cluster.GetDeletionTimestamp() != nil {
+				continue
+			}
+
+			annotations := cluster.GetAnnotations()
+
+			_, ready := annotations[constants.PoolReadyAnnotation]
+			_, leased := annotations[constants.LeaseHolderAnnotation]
+
+			var reason string
+
+			switch {
+			case ready && !leased:
+				idle++
+
+				continue
+			case !leased:
+				if time.Since(cluster.GetCreationTimestamp().Time) < pool.options.DeployTimeout {
+					deploying++
+
+					continue
+				}
+
+				// the deploy failed or the deploying process is gone
+				reason = "not ready after the deploy timeout"
+			default:
+				expiresAt, parseErr := time.Parse(time.RFC3339, annotations[constants.LeaseExpiresAtAnnotation])
+				if parseErr == nil && expiresAt.After(time.Now()) {
+					continue
+				}
+
+				// the holder is gone, the cluster state is unknown
+				reason = "with expired lease"
+			}
+
+			eg.Go(func() error {
+				if e := pool.manager.DestroyCluster(ctx, cluster.GetName(), cluster.GetNamespace()); e != nil {
+					collect(fmt.Errorf("failed to destroy cluster %s %s: %w", cluster.GetName(), reason, e))
+				}
+
+				return nil
+			})
+		}
+
+		for range profile.Size - idle - deploying {
+			eg.Go(func() error {
+				if e := pool.deploy(ctx, profile); e != nil {
+					collect(fmt.Errorf("failed to deploy cluster of the profile %q: %w", profile.Name, e))
+				}
+
+				return nil
+			})
+		}
+	}
+
+	eg.Wait() //nolint:errcheck
+
+	return errors.Join(errs...)
+}
+
+// Run replenishes the pool every interval until the context is canceled.
+func (pool *Pool) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := pool.Replenish(ctx); err != nil {
+			pool.manager.logger.Error("failed to replenish the cluster pool", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// deploy creates the profile cluster and marks it ready, the cluster is destroyed if the deploy fails.
+func (pool *Pool) deploy(ctx context.Context, profile PoolProfile) (err error) {
+	name := fmt.Sprintf("%s-%s", profile.Name, rand.String(5))
+
+	opts := append([]DeployOption{}, profile.Options...)
+	opts = append(opts,
+		WithClusterNamespace(pool.namespace),
+		WithLabels(map[string]string{
+			constants.PoolProfileLabel: profile.Name,
+		}),
+	)
+
+	defer func() {
+		if err == nil {
+			return
+		}
+
+		if e := pool.manager.DestroyCluster(ctx, name, pool.namespace); e != nil {
+			err = errors.Join(err, fmt.Errorf("failed to destroy the failed cluster %s: %w", name, e))
+		}
+	}()
+
+	cluster, err := pool.manager.DeployCluster(ctx, name, opts...)
+	if err != nil {
+		return err
+	}
+
+	if err = cluster.sync(ctx); err != nil {
+		return err
+	}
+
+	annotations := cluster.cluster.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	annotations[constants.PoolReadyAnnotation] = ""
+
+	cluster.cluster.SetAnnotations(annotations)
+
+	return pool.manager.runtimeClient.Update(ctx, &cluster.cluster)
+}
+
+func (pool *Pool) clusters(ctx context.Context, profile string) (*unstructured.UnstructuredList, error) {
+	return pool.manager.ListClusters(ctx, pool.namespace, ManagedSelector(map[string]string{
+		constants.PoolProfileLabel: profile,
+	}))
+}
@@ -0,0 +1,181 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/siderolabs/go-retry/retry"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// RolloutResult contains the machines of the nodes group before and after the rollout.
+type RolloutResult struct {
+	Before []string
+	After  []string
+}
+
+// Rollout triggers rolling replacement of all machines in the nodes group
+// and waits until every old machine is replaced and the cluster is healthy again.
+//
+//nolint:gocognit
+func (cluster *Cluster) Rollout(ctx context.Context, nodes NodeGroup) (*RolloutResult, error) {
+	objects, err := cluster.rolloutObjects(ctx, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := cluster.rolloutMachines(ctx, objects, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	rolloutAfter := time.Now().UTC().Format(time.RFC3339)
+
+	for _, object := range objects {
+		if err = unstructured.SetNestedField(object.Object, rolloutAfter, "spec", "rolloutAfter"); err != nil {
+			return nil, err
+		}
+
+		if err = cluster.manager.runtimeClient.Update(ctx, object); err != nil {
+			return nil, err
+		}
+
+		// the API server prunes the field if the CRD version does not support it, the rollout would never start
+		if err = cluster.manager.runtimeClient.Get(ctx, types.NamespacedName{Name: object.GetName(), Namespace: object.GetNamespace()}, object); err != nil {
+			return nil, err
+		}
+
+		if value, _, _ := unstructured.NestedString(object.Object, "spec", "rolloutAfter"); value != rolloutAfter { //nolint:errcheck
+			return nil, fmt.Errorf("%s %s does not support spec.rolloutAfter", object.GetKind(), object.GetName())
+		}
+	}
+
+	// give controllers some time to notice rolloutAfter
+	// so that the old machines do not look like the rollout result
+	time.Sleep(2 * time.Second)
+
+	var after []string
+
+	err = retry.Constant(60*time.Minute, retry.WithUnits(10*time.Second)).Retry(logRetries(cluster.logger(), func() error {
+		objects, err = cluster.rolloutObjects(ctx, nodes)
+		if err != nil {
+			return err
+		}
+
+		machines, e := cluster.rolloutMachines(ctx, objects, nodes)
+		if e != nil {
+			return e
+		}
+
+		var remaining int
+
+		for _, machine := range machines {
+			if slices.Contains(before, machine) {
+				remaining++
+			}
+		}
+
+		if remaining > 0 {
+			return retry.ExpectedErrorf("%d of %d old machines are not replaced yet", remaining, len(before))
+		}
+
+		var expected, replicas int64
+
+		for _, object := range objects {
+			replicas, _, e = unstructured.NestedInt64(object.Object, "spec", "replicas")
+			if e != nil {
+				return e
+			}
+
+			expected += replicas
+		}
+
+		if int64(len(machines)) != expected {
+			return retry.ExpectedErrorf("expected %d machines, current machines count: %d", expected, len(machines))
+		}
+
+		if e = cluster.manager.CheckClusterReady(ctx, cluster); e != nil {
+			return e
+		}
+
+		after = machines
+
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cluster.Sync(ctx); err != nil {
+		return nil, err
+	}
+
+	if err = cluster.Health(ctx); err != nil {
+		return nil, err
+	}
+
+	return &RolloutResult{
+		Before: before,
+		After:  after,
+	}, nil
+}
+
+func (cluster *Cluster) rolloutObjects(ctx context.Context, nodes NodeGroup) ([]*unstructured.Unstructured, error) {
+	switch nodes {
+	case ControlPlaneNodes:
+		controlPlane, err := cluster.ControlPlanes(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*unstructured.Unstructured{controlPlane}, nil
+	case WorkerNodes:
+		machineDeployments, err := cluster.Workers(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(machineDeployments.Items) == 0 {
+			return nil, fmt.Errorf("cluster has no machine deployments")
+		}
+
+		res := make([]*unstructured.Unstructured, 0, len(machineDeployments.Items))
+
+		for i := range machineDeployments.Items {
+			res = append(res, &machineDeployments.Items[i])
+		}
+
+		return res, nil
+	case MachinePoolNodes:
+		return nil, fmt.Errorf("rollout is not supported for machine pools")
+	default:
+		return nil, fmt.Errorf("unknown nodes group %d", nodes)
+	}
+}
+
+// rolloutMachines returns sorted names of the machines owned by the objects.
+func (cluster *Cluster) rolloutMachines(ctx context.Context, objects []*unstructured.Unstructured, nodes NodeGroup) ([]string, error) {
+	var res []string
+
+	for _, object := range objects {
+		machines, err := cluster.groupMachines(ctx, object, nodes)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, machine := range machines {
+			res = append(res, machine.GetName())
+		}
+	}
+
+	slices.Sort(res)
+
+	return res, nil
+}
@@ -6,12 +6,20 @@ package capi
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/siderolabs/go-retry/retry"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // NodeGroup defines CAPI cluster node type group.
@@ -22,11 +30,19 @@ const (
 	ControlPlaneNodes NodeGroup = iota
 	// WorkerNodes worker nodes group.
 	WorkerNodes
+	// MachinePoolNodes machine pool nodes group.
+	MachinePoolNodes
 )
 
 // ScaleOptions defines additional optional parameters for scale method.
 type ScaleOptions struct {
-	MachineDeploymentName string
+	MachineDeploymentReplicas map[string]int
+	MachineDeploymentName     string
+	MachineDeploymentSelector string
+	MachinePoolName           string
+	MachinesToDelete          []string
+	AllMachineDeployments     bool
+	Force                     bool
 }
 
 // ScaleOption optional scale parameter setter.
@@ -40,11 +56,86 @@ func MachineDeploymentName(name string) ScaleOption {
 	}
 }
 
+// MachinePoolName allows setting machine pool name for scaling
+// clusters that have more than one machine pool.
+func MachinePoolName(name string) ScaleOption {
+	return func(opts *ScaleOptions) {
+		opts.MachinePoolName = name
+	}
+}
+
+// MachineDeploymentSelector scales all machine deployments matching the label selector.
+//
+// When scaling MachinePoolNodes the selector is applied to the machine pools.
+func MachineDeploymentSelector(selector string) ScaleOption {
+	return func(opts *ScaleOptions) {
+		opts.MachineDeploymentSelector = selector
+	}
+}
+
+// AllMachineDeployments scales all machine deployments of the cluster.
+//
+// When scaling MachinePoolNodes all machine pools are scaled.
+func AllMachineDeployments() ScaleOption {
+	return func(opts *ScaleOptions) {
+		opts.AllMachineDeployments = true
+	}
+}
+
+// MachineDeploymentReplicas overrides replicas count per machine deployment name.
+//
+// If no other machine deployment filter is set, only the deployments from the map are scaled.
+// When scaling MachinePoolNodes the map keys are machine pool names.
+func MachineDeploymentReplicas(replicas map[string]int) ScaleOption {
+	return func(opts *ScaleOptions) {
+		opts.MachineDeploymentReplicas = replicas
+	}
+}
+
+// DeleteMachines allows picking the machines which should be removed when scaling down.
+// Each entry can be either a Machine name or a name of the node backing the Machine.
+func DeleteMachines(names ...string) ScaleOption {
+	return func(opts *ScaleOptions) {
+		opts.MachinesToDelete = append(opts.MachinesToDelete, names...)
+	}
+}
+
+// ForceScale allows manual scaling of the machine deployments managed by the cluster autoscaler.
+func ForceScale() ScaleOption {
+	return func(opts *ScaleOptions) {
+		opts.Force = true
+	}
+}
+
+// scaledTarget is the scale target with the replicas count before the update.
+type scaledTarget struct {
+	target *scaleTarget
+	from   int64
+}
+
+type scaleTarget struct {
+	object          *unstructured.Unstructured
+	deletedMachines map[string]struct{}
+	machines        []unstructured.Unstructured
+	// marked are the machines annotated for deletion by this scale call.
+	marked   []*unstructured.Unstructured
+	replicas int
+	// updated is set once the new replicas count is written.
+	updated bool
+}
+
 // Scale cluster nodes.
 //
 //nolint:gocognit,gocyclo,cyclop
-func (cluster *Cluster) Scale(ctx context.Context, replicas int, nodes NodeGroup, setters ...ScaleOption) error {
-	var object *unstructured.Unstructured
+func (cluster *Cluster) Scale(ctx context.Context, replicas int, nodes NodeGroup, setters ...ScaleOption) (err error) {
+	metric := startOperationMetric(operationScale, cluster.providerLabel(), clusterLabel(cluster.name, cluster.namespace))
+	defer func() { metric.finish(err) }()
+
+	ctx, span := startSpan(ctx, "Scale", append(cluster.spanAttributes(),
+		attribute.Int("capi.replicas", replicas),
+		attribute.Int("capi.node_group", int(nodes)),
+	)...)
+	defer func() { endSpan(span, err) }()
 
 	var opts ScaleOptions
 
@@ -52,62 +143,90 @@ func (cluster *Cluster) Scale(ctx context.Context, replicas int, nodes NodeGroup
 		s(&opts)
 	}
 
-	switch nodes {
-	case ControlPlaneNodes:
-		controlPlane, err := cluster.ControlPlanes(ctx)
-		if err != nil {
+	targets, err := cluster.scaleTargets(ctx, replicas, nodes, &opts)
+	if err != nil {
+		return err
+	}
+
+	cluster.logger().Info("scaling cluster", logKeyPhase, "scale", "replicas", replicas, "targets", len(targets))
+
+	if len(opts.MachinesToDelete) > 0 {
+		// the annotations left behind make an unrelated scale down delete these machines first
+		defer func() {
+			if err != nil {
+				err = errors.Join(err, cluster.unmarkMachinesForDeletion(ctx, targets))
+			}
+		}()
+
+		if err = cluster.markMachinesForDeletion(ctx, targets, nodes, opts.MachinesToDelete); err != nil {
 			return err
 		}
+	}
 
-		object = controlPlane
-	case WorkerNodes:
-		machineDeployments, err := cluster.Workers(ctx)
+	if err = cluster.sync(ctx); err != nil {
+		return err
+	}
+
+	topologyManaged := cluster.TopologyManaged()
+
+	var scaled []scaledTarget
+
+	defer func() {
+		if err != nil && len(scaled) > 0 {
+			cluster.recordEvent(corev1.EventTypeWarning, EventReasonScaleFailed, "Failed to scale the cluster: %s", err)
+		}
+	}()
+
+	for _, target := range targets {
+		var current int64
+
+		current, _, err = unstructured.NestedInt64(target.object.Object, "spec", "replicas")
 		if err != nil {
 			return err
 		}
 
-		if len(machineDeployments.Items) == 0 {
-			return fmt.Errorf("cluster has no machine deployments")
+		// nothing to do
+		if current == int64(target.replicas) {
+			continue
 		}
 
-		var deployment *unstructured.Unstructured
+		scaled = append(scaled, scaledTarget{target: target, from: current})
+
+		cluster.recordEvent(corev1.EventTypeNormal, EventReasonScaling, "Scaling %s %s from %d to %d replicas",
+			target.object.GetKind(), target.object.GetName(), current, target.replicas)
 
-		if len(machineDeployments.Items) > 1 {
-			if opts.MachineDeploymentName == "" {
-				return fmt.Errorf("cluster has several machine deployments, please provide MachineDeploymentName")
+		// topology controller owns the replicas of the managed objects
+		if topologyManaged {
+			if err = cluster.setTopologyReplicas(target, nodes); err != nil {
+				return err
 			}
 
-			for i, d := range machineDeployments.Items {
-				if d.GetName() == opts.MachineDeploymentName {
-					deployment = &machineDeployments.Items[i]
+			continue
+		}
 
-					break
-				}
-			}
-		} else {
-			deployment = &machineDeployments.Items[0]
+		if err = unstructured.SetNestedField(target.object.Object, int64(target.replicas), "spec", "replicas"); err != nil {
+			return err
 		}
 
-		object = deployment
-	default:
-		return fmt.Errorf("unknown nodes group %d", nodes)
-	}
+		if err = cluster.manager.runtimeClient.Update(ctx, target.object); err != nil {
+			return err
+		}
 
-	spec, _, err := unstructured.NestedMap(object.Object, "spec")
-	if err != nil {
-		return err
+		target.updated = true
 	}
 
-	// nothing to do
-	if spec["replicas"] == replicas {
+	if len(scaled) == 0 {
 		return nil
 	}
 
-	spec["replicas"] = replicas
-	object.Object["spec"] = spec
+	if topologyManaged {
+		if err = cluster.manager.runtimeClient.Update(ctx, &cluster.cluster); err != nil {
+			return err
+		}
 
-	if err = cluster.manager.runtimeClient.Update(ctx, object); err != nil {
-		return err
+		for _, s := range scaled {
+			s.target.updated = true
+		}
 	}
 
 	// unstarted scale up/down may look like completed one
@@ -115,15 +234,31 @@ func (cluster *Cluster) Scale(ctx context.Context, replicas int, nodes NodeGroup
 	// so wait a bit until it actually starts scaling
 	time.Sleep(2 * time.Second)
 
-	err = retry.Constant(30*time.Minute, retry.WithUnits(10*time.Second), retry.WithErrorLogging(true)).Retry(func() error {
-		if e := cluster.manager.runtimeClient.Get(ctx, types.NamespacedName{Name: object.GetName(), Namespace: object.GetNamespace()}, object); e != nil {
-			return e
-		}
+	eg, egCtx := errgroup.WithContext(ctx)
 
-		if c := getReplicas(object, "replicas"); c != int64(replicas) {
-			return retry.ExpectedErrorf("expected %d, current replicas count: %d", replicas, c)
-		}
+	for _, target := range targets {
+		eg.Go(func() error {
+			return retry.Constant(30*time.Minute, retry.WithUnits(10*time.Second)).RetryWithContext(egCtx, logRetriesWithContext(cluster.logger(), func(ctx context.Context) error {
+				object := target.object
+
+				if e := cluster.manager.runtimeClient.Get(ctx, types.NamespacedName{Name: object.GetName(), Namespace: object.GetNamespace()}, object); e != nil {
+					return e
+				}
+
+				if c := getReplicas(object, "replicas"); c != int64(target.replicas) {
+					return retry.ExpectedErrorf("%s %s expected %d, current replicas count: %d", object.GetKind(), object.GetName(), target.replicas, c)
+				}
+
+				return nil
+			}))
+		})
+	}
 
+	if err = eg.Wait(); err != nil {
+		return err
+	}
+
+	err = retry.Constant(30*time.Minute, retry.WithUnits(10*time.Second)).Retry(logRetries(cluster.logger(), func() error {
 		if e := cluster.manager.CheckClusterReady(ctx, cluster); e != nil {
 			return e
 		}
@@ -132,26 +267,243 @@ func (cluster *Cluster) Scale(ctx context.Context, replicas int, nodes NodeGroup
 			return e
 		}
 
-		var actualReplicas int
+		var expectedReplicas, actualReplicas int
 
 		switch nodes {
 		case ControlPlaneNodes:
+			expectedReplicas = replicas
 			actualReplicas = len(cluster.controlPlaneNodes)
-		case WorkerNodes:
+		case WorkerNodes, MachinePoolNodes:
+			count, e := cluster.workerReplicas(ctx)
+			if e != nil {
+				return e
+			}
+
+			expectedReplicas = count
 			actualReplicas = len(cluster.workerNodes)
 		}
 
-		if actualReplicas != replicas {
-			return retry.ExpectedErrorf("get nodes expected %d, current nodes count: %d", replicas, actualReplicas)
+		if actualReplicas != expectedReplicas {
+			return retry.ExpectedErrorf("get nodes expected %d, current nodes count: %d", expectedReplicas, actualReplicas)
+		}
+
+		for _, target := range targets {
+			if target.deletedMachines == nil {
+				continue
+			}
+
+			if e := cluster.checkMachinesDeleted(ctx, target, nodes); e != nil {
+				return e
+			}
 		}
 
 		return nil
-	})
+	}))
 	if err != nil {
 		return err
 	}
 
-	return cluster.Sync(ctx)
+	if err = cluster.Sync(ctx); err != nil {
+		return err
+	}
+
+	for _, s := range scaled {
+		cluster.recordEvent(corev1.EventTypeNormal, EventReasonScaled, "Scaled %s %s from %d to %d replicas",
+			s.target.object.GetKind(), s.target.object.GetName(), s.from, s.target.replicas)
+	}
+
+	return nil
+}
+
+// scaleTargets picks the objects to scale and the desired replicas count for each of them.
+//
+//nolint:gocognit,gocyclo,cyclop
+func (cluster *Cluster) scaleTargets(ctx context.Context, replicas int, nodes NodeGroup, opts *ScaleOptions) ([]*scaleTarget, error) {
+	switch nodes {
+	case ControlPlaneNodes:
+		controlPlane, err := cluster.ControlPlanes(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*scaleTarget{
+			{
+				object:   controlPlane,
+				replicas: replicas,
+			},
+		}, nil
+	case WorkerNodes, MachinePoolNodes:
+	default:
+		return nil, fmt.Errorf("unknown nodes group %d", nodes)
+	}
+
+	var (
+		groups *unstructured.UnstructuredList
+		kind   string
+		name   string
+		err    error
+	)
+
+	if nodes == MachinePoolNodes {
+		kind, name = "machine pool", opts.MachinePoolName
+
+		groups, err = cluster.MachinePools(ctx)
+	} else {
+		kind, name = "machine deployment", opts.MachineDeploymentName
+
+		groups, err = cluster.Workers(ctx)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(groups.Items) == 0 {
+		return nil, fmt.Errorf("cluster has no %ss", kind)
+	}
+
+	for groupName := range opts.MachineDeploymentReplicas {
+		if !slices.ContainsFunc(groups.Items, func(d unstructured.Unstructured) bool { return d.GetName() == groupName }) {
+			return nil, fmt.Errorf("%s %q is not found", kind, groupName)
+		}
+	}
+
+	var match func(d *unstructured.Unstructured) bool
+
+	switch {
+	case name != "":
+		match = func(d *unstructured.Unstructured) bool {
+			return d.GetName() == name
+		}
+	case opts.MachineDeploymentSelector != "":
+		var selector labels.Selector
+
+		selector, err = labels.Parse(opts.MachineDeploymentSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		match = func(d *unstructured.Unstructured) bool {
+			return selector.Matches(labels.Set(d.GetLabels()))
+		}
+	case opts.AllMachineDeployments:
+		match = func(*unstructured.Unstructured) bool {
+			return true
+		}
+	case len(opts.MachineDeploymentReplicas) > 0:
+		match = func(d *unstructured.Unstructured) bool {
+			_, ok := opts.MachineDeploymentReplicas[d.GetName()]
+
+			return ok
+		}
+	case len(groups.Items) > 1:
+		return nil, validationError(fmt.Errorf("cluster has several %ss, please provide the name, the selector or select all of them", kind))
+	default:
+		match = func(*unstructured.Unstructured) bool {
+			return true
+		}
+	}
+
+	var targets []*scaleTarget
+
+	for i := range groups.Items {
+		group := &groups.Items[i]
+
+		if !match(group) {
+			continue
+		}
+
+		target := &scaleTarget{
+			object:   group,
+			replicas: replicas,
+		}
+
+		if count, ok := opts.MachineDeploymentReplicas[group.GetName()]; ok {
+			target.replicas = count
+		}
+
+		if target.replicas < 0 {
+			return nil, validationError(fmt.Errorf("invalid replicas count %d for %s %q", target.replicas, kind, group.GetName()))
+		}
+
+		if _, autoscaled := group.GetAnnotations()[clusterv1.AutoscalerMaxSizeAnnotation]; autoscaled && !opts.Force {
+			return nil, validationError(fmt.Errorf("%s %q is managed by the cluster autoscaler, use ForceScale to scale it manually", kind, group.GetName()))
+		}
+
+		targets = append(targets, target)
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("no %ss matched", kind)
+	}
+
+	return targets, nil
+}
+
+// setTopologyReplicas updates the replicas of the target in the cluster topology.
+func (cluster *Cluster) setTopologyReplicas(target *scaleTarget, nodes NodeGroup) error {
+	var label, field string
+
+	switch nodes {
+	case ControlPlaneNodes:
+		return unstructured.SetNestedField(cluster.cluster.Object, int64(target.replicas), "spec", "topology", "controlPlane", "replicas")
+	case WorkerNodes:
+		label, field = clusterv1.ClusterTopologyMachineDeploymentNameLabel, "machineDeployments"
+	case MachinePoolNodes:
+		label, field = clusterv1.ClusterTopologyMachinePoolNameLabel, "machinePools"
+	default:
+		return fmt.Errorf("unknown nodes group %d", nodes)
+	}
+
+	name := target.object.GetLabels()[label]
+
+	topologies, _, err := unstructured.NestedSlice(cluster.cluster.Object, "spec", "topology", "workers", field)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range topologies {
+		topology, ok := t.(map[string]any)
+		if !ok {
+			return fmt.Errorf("failed to convert topology to map[string]interface{}")
+		}
+
+		if topology["name"] != name {
+			continue
+		}
+
+		topology["replicas"] = int64(target.replicas)
+
+		return unstructured.SetNestedSlice(cluster.cluster.Object, topologies, "spec", "topology", "workers", field)
+	}
+
+	return fmt.Errorf("%s %s is not found in the cluster topology", target.object.GetKind(), target.object.GetName())
+}
+
+// workerReplicas returns the total desired count of the worker nodes.
+func (cluster *Cluster) workerReplicas(ctx context.Context) (int, error) {
+	machineDeployments, err := cluster.Workers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	machinePools, err := cluster.MachinePools(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var count, replicas int64
+
+	for _, deployment := range append(machineDeployments.Items, machinePools.Items...) {
+		replicas, _, err = unstructured.NestedInt64(deployment.Object, "spec", "replicas")
+		if err != nil {
+			return 0, err
+		}
+
+		count += replicas
+	}
+
+	return int(count), nil
 }
 
 func getReplicas(object *unstructured.Unstructured, key string) int64 {
@@ -166,3 +518,193 @@ func getReplicas(object *unstructured.Unstructured, key string) int64 {
 
 	return value
 }
+
+// groupMachines returns the machines owned by the scaled object.
+func (cluster *Cluster) groupMachines(ctx context.Context, object *unstructured.Unstructured, nodes NodeGroup) ([]unstructured.Unstructured, error) {
+	machines, err := cluster.Machines(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]unstructured.Unstructured, 0, len(machines.Items))
+
+	for _, machine := range machines.Items {
+		machineLabels := machine.GetLabels()
+
+		switch nodes {
+		case ControlPlaneNodes:
+			if _, ok := machineLabels[clusterv1.MachineControlPlaneLabel]; !ok {
+				continue
+			}
+		case WorkerNodes:
+			if machineLabels[clusterv1.MachineDeploymentNameLabel] != object.GetName() {
+				continue
+			}
+		case MachinePoolNodes:
+			if machineLabels[clusterv1.MachinePoolNameLabel] != object.GetName() {
+				continue
+			}
+		}
+
+		res = append(res, machine)
+	}
+
+	return res, nil
+}
+
+// markMachinesForDeletion resolves machines by machine or node names and sets the delete-machine annotation on them.
+//
+//nolint:gocognit
+func (cluster *Cluster) markMachinesForDeletion(ctx context.Context, targets []*scaleTarget, nodes NodeGroup, names []string) error {
+	var (
+		resolved = map[string]struct{}{}
+		nodeName string
+		err      error
+	)
+
+	for _, target := range targets {
+		target.machines, err = cluster.groupMachines(ctx, target.object, nodes)
+		if err != nil {
+			return err
+		}
+
+		target.deletedMachines = map[string]struct{}{}
+
+		for _, machine := range target.machines {
+			nodeName, _, err = unstructured.NestedString(machine.Object, "status", "nodeRef", "name")
+			if err != nil {
+				return err
+			}
+
+			for _, name := range names {
+				if machine.GetName() == name || (nodeName != "" && nodeName == name) {
+					target.deletedMachines[machine.GetName()] = struct{}{}
+					resolved[name] = struct{}{}
+				}
+			}
+		}
+	}
+
+	for _, name := range names {
+		if _, ok := resolved[name]; !ok {
+			return fmt.Errorf("machine or node %q is not found", name)
+		}
+	}
+
+	for _, target := range targets {
+		object := target.object
+
+		if len(target.deletedMachines) == 0 {
+			target.deletedMachines = nil
+
+			continue
+		}
+
+		var currentReplicas int64
+
+		currentReplicas, _, err = unstructured.NestedInt64(object.Object, "spec", "replicas")
+		if err != nil {
+			return err
+		}
+
+		if removed := currentReplicas - int64(target.replicas); int64(len(target.deletedMachines)) != removed {
+			return validationError(fmt.Errorf("scaling %s %s from %d to %d replicas removes %d machines, but %d machines were requested for deletion",
+				object.GetKind(), object.GetName(), currentReplicas, target.replicas, removed, len(target.deletedMachines)))
+		}
+
+		for i := range target.machines {
+			machine := &target.machines[i]
+
+			if _, ok := target.deletedMachines[machine.GetName()]; !ok {
+				continue
+			}
+
+			annotations := machine.GetAnnotations()
+			if _, ok := annotations[clusterv1.DeleteMachineAnnotation]; ok {
+				continue
+			}
+
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+
+			annotations[clusterv1.DeleteMachineAnnotation] = "yes"
+
+			machine.SetAnnotations(annotations)
+
+			if err = cluster.manager.runtimeClient.Update(ctx, machine); err != nil {
+				return err
+			}
+
+			target.marked = append(target.marked, machine)
+		}
+	}
+
+	return nil
+}
+
+// unmarkMachinesForDeletion removes the delete annotation added by the failed scale call
+// from the machines of the targets which replicas were not updated.
+func (cluster *Cluster) unmarkMachinesForDeletion(ctx context.Context, targets []*scaleTarget) error {
+	// the cleanup runs even if the scale was canceled
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Minute)
+	defer cancel()
+
+	var errs []error
+
+	for _, target := range targets {
+		if target.updated {
+			continue
+		}
+
+		for _, machine := range target.marked {
+			if err := cluster.manager.runtimeClient.Get(ctx, types.NamespacedName{Name: machine.GetName(), Namespace: machine.GetNamespace()}, machine); err != nil {
+				if !apierrors.IsNotFound(err) {
+					errs = append(errs, fmt.Errorf("failed to remove the delete annotation from machine %s: %w", machine.GetName(), err))
+				}
+
+				continue
+			}
+
+			annotations := machine.GetAnnotations()
+			delete(annotations, clusterv1.DeleteMachineAnnotation)
+			machine.SetAnnotations(annotations)
+
+			if err := cluster.manager.runtimeClient.Update(ctx, machine); err != nil {
+				errs = append(errs, fmt.Errorf("failed to remove the delete annotation from machine %s: %w", machine.GetName(), err))
+			}
+		}
+
+		target.marked = nil
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkMachinesDeleted verifies that exactly the requested machines were removed from the group.
+func (cluster *Cluster) checkMachinesDeleted(ctx context.Context, target *scaleTarget, nodes NodeGroup) error {
+	machinesAfter, err := cluster.groupMachines(ctx, target.object, nodes)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]struct{}, len(machinesAfter))
+
+	for _, machine := range machinesAfter {
+		existing[machine.GetName()] = struct{}{}
+	}
+
+	for _, machine := range target.machines {
+		_, exists := existing[machine.GetName()]
+		_, deleted := target.deletedMachines[machine.GetName()]
+
+		switch {
+		case deleted && exists:
+			return retry.ExpectedErrorf("machine %s is still being deleted", machine.GetName())
+		case !deleted && !exists:
+			return fmt.Errorf("machine %s was removed, but it was not requested for deletion", machine.GetName())
+		}
+	}
+
+	return nil
+}
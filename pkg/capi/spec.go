@@ -0,0 +1,388 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metavalidation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	"github.com/siderolabs/capi-utils/pkg/capi/infrastructure"
+	"github.com/siderolabs/capi-utils/pkg/constants"
+)
+
+const (
+	// ClusterSpecAPIVersion is the supported version of the cluster spec file.
+	ClusterSpecAPIVersion = "capi-utils.siderolabs.dev/v1alpha1"
+	// ClusterSpecKind is the kind of the cluster spec file.
+	ClusterSpecKind = "ClusterSpec"
+)
+
+// ClusterSpec is a declarative cluster definition which can be stored as YAML or JSON.
+type ClusterSpec struct {
+	Labels       map[string]string       `json:"labels,omitempty"`
+	Annotations  map[string]string       `json:"annotations,omitempty"`
+	TTL          *metav1.Duration        `json:"ttl,omitempty"`
+	Provider     ClusterSpecProvider     `json:"provider"`
+	APIVersion   string                  `json:"apiVersion"`
+	Kind         string                  `json:"kind"`
+	Name         string                  `json:"name,omitempty"`
+	Namespace    string                  `json:"namespace,omitempty"`
+	Versions     ClusterSpecVersions     `json:"versions"`
+	Template     ClusterSpecTemplate     `json:"template"`
+	ControlPlane ClusterSpecControlPlane `json:"controlPlane"`
+	Workers      ClusterSpecWorkers      `json:"workers"`
+}
+
+// ClusterSpecProvider defines the infrastructure provider and its deploy options.
+type ClusterSpecProvider struct {
+	AWS     *infrastructure.AWSDeployOptions `json:"aws,omitempty"`
+	Name    string                           `json:"name,omitempty"`
+	Version string                           `json:"version,omitempty"`
+}
+
+// ClusterSpecVersions defines Talos and Kubernetes versions.
+type ClusterSpecVersions struct {
+	Talos      string `json:"talos,omitempty"`
+	Kubernetes string `json:"kubernetes,omitempty"`
+}
+
+// ClusterSpecTemplate defines the source of the cluster objects.
+type ClusterSpecTemplate struct {
+	Variables    map[string]any `json:"variables,omitempty"`
+	File         string         `json:"file,omitempty"`
+	Checksum     string         `json:"checksum,omitempty"`
+	ClusterClass string         `json:"clusterClass,omitempty"`
+}
+
+// ClusterSpecControlPlane defines control plane nodes.
+type ClusterSpecControlPlane struct {
+	Nodes *int64 `json:"nodes,omitempty"`
+	// ConfigPatches are Talos machine config patches, see WithControlPlaneConfigPatches.
+	ConfigPatches []string `json:"configPatches,omitempty"`
+}
+
+// ClusterSpecWorkers defines worker nodes.
+type ClusterSpecWorkers struct {
+	Nodes *int64                  `json:"nodes,omitempty"`
+	Pools []ClusterSpecWorkerPool `json:"pools,omitempty"`
+	// ConfigPatches are Talos machine config patches, see WithWorkerConfigPatches.
+	ConfigPatches []string `json:"configPatches,omitempty"`
+}
+
+// ClusterSpecWorkerPool defines a named worker pool.
+type ClusterSpecWorkerPool struct {
+	Labels map[string]string `json:"labels,omitempty"`
+	// AWS overrides the cluster AWS deploy options for the pool machines.
+	AWS      json.RawMessage `json:"aws,omitempty"`
+	Name     string          `json:"name"`
+	Taints   []corev1.Taint  `json:"taints,omitempty"`
+	Replicas int64           `json:"replicas"`
+}
+
+// LoadClusterSpec decodes and validates the cluster spec from YAML or JSON.
+//
+// Unknown fields are rejected.
+func LoadClusterSpec(data []byte) (*ClusterSpec, error) {
+	spec := &ClusterSpec{}
+
+	if err := yaml.UnmarshalStrict(data, spec); err != nil {
+		return nil, validationError(fmt.Errorf("failed to decode cluster spec: %w", err))
+	}
+
+	if err := spec.Validate(); err != nil {
+		return nil, validationError(fmt.Errorf("invalid cluster spec:\n%w", err))
+	}
+
+	return spec, nil
+}
+
+// Validate checks the cluster spec and reports all found problems at once.
+//
+//nolint:gocognit,gocyclo,cyclop
+func (spec *ClusterSpec) Validate() error {
+	var errs []error
+
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if spec.APIVersion != ClusterSpecAPIVersion {
+		fail("apiVersion", "unsupported version %q, expected %q", spec.APIVersion, ClusterSpecAPIVersion)
+	}
+
+	if spec.Kind != ClusterSpecKind {
+		fail("kind", "unsupported kind %q, expected %q", spec.Kind, ClusterSpecKind)
+	}
+
+	if spec.Name != "" {
+		for _, msg := range validation.IsDNS1123Subdomain(spec.Name) {
+			fail("name", "%s", msg)
+		}
+	}
+
+	if spec.Namespace != "" {
+		for _, msg := range validation.IsDNS1123Label(spec.Namespace) {
+			fail("namespace", "%s", msg)
+		}
+	}
+
+	for _, e := range metavalidation.ValidateLabels(spec.Labels, field.NewPath("labels")) {
+		fail(e.Field, "%s", e.ErrorBody())
+	}
+
+	for _, e := range apivalidation.ValidateAnnotations(spec.Annotations, field.NewPath("annotations")) {
+		fail(e.Field, "%s", e.ErrorBody())
+	}
+
+	if spec.TTL != nil && spec.TTL.Duration <= 0 {
+		fail("ttl", "must be positive, got %s", spec.TTL.Duration)
+	}
+
+	if spec.Provider.Name != "" {
+		if _, err := infrastructure.NewProvider(spec.Provider.Name); err != nil {
+			fail("provider.name", "%s", err)
+		}
+	}
+
+	if spec.ControlPlane.Nodes != nil && *spec.ControlPlane.Nodes < 1 {
+		fail("controlPlane.nodes", "must be at least 1, got %d", *spec.ControlPlane.Nodes)
+	}
+
+	if spec.Workers.Nodes != nil && *spec.Workers.Nodes < 0 {
+		fail("workers.nodes", "must not be negative, got %d", *spec.Workers.Nodes)
+	}
+
+	if _, err := loadTalosConfigPatches(spec.ControlPlane.ConfigPatches); err != nil {
+		fail("controlPlane.configPatches", "%s", err)
+	}
+
+	if _, err := loadTalosConfigPatches(spec.Workers.ConfigPatches); err != nil {
+		fail("workers.configPatches", "%s", err)
+	}
+
+	if spec.Workers.Nodes != nil && len(spec.Workers.Pools) > 0 {
+		fail("workers", "nodes and pools are mutually exclusive")
+	}
+
+	if spec.Template.File != "" && spec.Template.ClusterClass != "" {
+		fail("template", "file and clusterClass are mutually exclusive")
+	}
+
+	if spec.Template.Checksum != "" {
+		if err := WithTemplateChecksum(spec.Template.Checksum)(&DeployOptions{}); err != nil {
+			fail("template.checksum", "%s", err)
+		}
+	}
+
+	if len(spec.Template.Variables) > 0 && spec.Template.ClusterClass == "" {
+		fail("template.variables", "variables require clusterClass")
+	}
+
+	if len(spec.Workers.Pools) > 0 && spec.Template.ClusterClass != "" {
+		fail("workers.pools", "worker pools are not supported with clusterClass")
+	}
+
+	names := map[string]struct{}{}
+
+	for i, pool := range spec.Workers.Pools {
+		field := fmt.Sprintf("workers.pools[%d]", i)
+
+		if _, ok := names[pool.Name]; ok {
+			fail(field+".name", "duplicate worker pool %q", pool.Name)
+		}
+
+		names[pool.Name] = struct{}{}
+
+		workerPool := WorkerPool{Name: pool.Name, Replicas: pool.Replicas}
+
+		if err := workerPool.validate(); err != nil {
+			fail(field, "%s", err)
+		}
+
+		for j, taint := range pool.Taints {
+			if taint.Key == "" {
+				fail(fmt.Sprintf("%s.taints[%d].key", field, j), "must not be empty")
+			}
+
+			switch taint.Effect {
+			case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+			default:
+				fail(fmt.Sprintf("%s.taints[%d].effect", field, j), "unsupported effect %q", taint.Effect)
+			}
+		}
+
+		if len(pool.AWS) > 0 {
+			if _, err := poolAWSOptions(spec.Provider.AWS, &pool); err != nil {
+				fail(field+".aws", "%s", err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// WithClusterSpec sets deploy options from the cluster spec.
+func WithClusterSpec(spec *ClusterSpec) DeployOption {
+	return func(o *DeployOptions) error {
+		if err := WithLabels(spec.Labels)(o); err != nil {
+			return err
+		}
+
+		if err := WithAnnotations(spec.Annotations)(o); err != nil {
+			return err
+		}
+
+		if spec.TTL != nil {
+			if err := WithTTL(spec.TTL.Duration)(o); err != nil {
+				return err
+			}
+		}
+
+		if spec.Namespace != "" {
+			o.ClusterNamespace = spec.Namespace
+		}
+
+		if spec.Provider.Name != "" {
+			o.Provider = spec.Provider.Name
+		}
+
+		if spec.Provider.Version != "" {
+			o.ProviderVersion = spec.Provider.Version
+		}
+
+		if spec.Versions.Talos != "" {
+			o.TalosVersion = spec.Versions.Talos
+		}
+
+		if spec.Versions.Kubernetes != "" {
+			o.KubernetesVersion = spec.Versions.Kubernetes
+		}
+
+		if spec.ControlPlane.Nodes != nil {
+			o.ControlPlaneNodes = *spec.ControlPlane.Nodes
+		}
+
+		if spec.Workers.Nodes != nil {
+			o.WorkerNodes = *spec.Workers.Nodes
+		}
+
+		if err := WithControlPlaneConfigPatches(spec.ControlPlane.ConfigPatches...)(o); err != nil {
+			return err
+		}
+
+		if err := WithWorkerConfigPatches(spec.Workers.ConfigPatches...)(o); err != nil {
+			return err
+		}
+
+		if spec.Template.File != "" {
+			o.TemplateFile = spec.Template.File
+		}
+
+		if spec.Template.Checksum != "" {
+			if err := WithTemplateChecksum(spec.Template.Checksum)(o); err != nil {
+				return err
+			}
+		}
+
+		if spec.Template.ClusterClass != "" {
+			o.ClusterClass = spec.Template.ClusterClass
+			o.TopologyVariables = spec.Template.Variables
+		}
+
+		if spec.awsProvider() && spec.Provider.AWS != nil {
+			awsOptions, err := mergeAWSOptions(o.providerOptions, spec.Provider.AWS)
+			if err != nil {
+				return err
+			}
+
+			o.providerOptions = awsOptions
+		}
+
+		for _, pool := range spec.Workers.Pools {
+			workerPool := WorkerPool{
+				Name:     pool.Name,
+				Replicas: pool.Replicas,
+				Labels:   pool.Labels,
+				Taints:   pool.Taints,
+			}
+
+			if spec.awsProvider() && len(pool.AWS) > 0 {
+				awsOptions, err := poolAWSOptions(o.providerOptions, &pool)
+				if err != nil {
+					return err
+				}
+
+				workerPool.ProviderOptions = awsOptions
+			}
+
+			if err := WithWorkerPool(workerPool)(o); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func (spec *ClusterSpec) awsProvider() bool {
+	return spec.Provider.Name == "" || spec.Provider.Name == constants.AWSProviderName
+}
+
+// poolAWSOptions merges the pool AWS options over the cluster ones.
+func poolAWSOptions(base any, pool *ClusterSpecWorkerPool) (*infrastructure.AWSDeployOptions, error) {
+	res := awsOptionsCopy(base)
+
+	data, err := yaml.YAMLToJSON(pool.AWS)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err = decoder.Decode(res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// mergeAWSOptions merges the non-zero fields of the spec AWS options over the base ones.
+func mergeAWSOptions(base any, overlay *infrastructure.AWSDeployOptions) (*infrastructure.AWSDeployOptions, error) {
+	res := awsOptionsCopy(base)
+
+	// zero fields are omitted, so only the fields set in the spec override the base options
+	data, err := json.Marshal(overlay)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// awsOptionsCopy copies the AWS deploy options, the defaults are used if the base options are not set.
+func awsOptionsCopy(base any) *infrastructure.AWSDeployOptions {
+	res := infrastructure.NewAWSDeployOptions()
+
+	if opts, ok := base.(*infrastructure.AWSDeployOptions); ok && opts != nil {
+		*res = *opts
+	}
+
+	return res
+}
@@ -0,0 +1,174 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/siderolabs/talos/pkg/machinery/config/configpatcher"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// WithControlPlaneConfigPatches adds Talos machine config patches for the control plane nodes.
+//
+// Patches are either strategic merge patches or JSON patches in the talosctl format,
+// patches starting with @ are read from the file.
+func WithControlPlaneConfigPatches(patches ...string) DeployOption {
+	return func(o *DeployOptions) error {
+		loaded, err := loadTalosConfigPatches(patches)
+		if err != nil {
+			return fmt.Errorf("invalid control plane config patch: %w", err)
+		}
+
+		o.ControlPlaneConfigPatches = append(o.ControlPlaneConfigPatches, loaded...)
+
+		return nil
+	}
+}
+
+// WithWorkerConfigPatches adds Talos machine config patches for the worker nodes.
+//
+// Patches are either strategic merge patches or JSON patches in the talosctl format,
+// patches starting with @ are read from the file.
+func WithWorkerConfigPatches(patches ...string) DeployOption {
+	return func(o *DeployOptions) error {
+		loaded, err := loadTalosConfigPatches(patches)
+		if err != nil {
+			return fmt.Errorf("invalid worker config patch: %w", err)
+		}
+
+		o.WorkerConfigPatches = append(o.WorkerConfigPatches, loaded...)
+
+		return nil
+	}
+}
+
+// loadTalosConfigPatches reads the patch files and validates the patches with the Talos config loader.
+func loadTalosConfigPatches(patches []string) ([]string, error) {
+	res := make([]string, 0, len(patches))
+
+	for _, patch := range patches {
+		if filename, ok := strings.CutPrefix(patch, "@"); ok {
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				return nil, err
+			}
+
+			patch = string(data)
+		}
+
+		if _, err := configpatcher.LoadPatch([]byte(patch)); err != nil {
+			return nil, err
+		}
+
+		res = append(res, patch)
+	}
+
+	return res, nil
+}
+
+// applyTalosConfigPatches adds the config patches to the TalosControlPlane and TalosConfigTemplate objects.
+//
+// JSON patches go to configPatches, strategic merge patches go to strategicPatches.
+func applyTalosConfigPatches(objs []unstructured.Unstructured, options *DeployOptions) error {
+	for _, target := range []struct {
+		kind    string
+		patches []string
+		fields  []string
+	}{
+		{
+			kind:    "TalosControlPlane",
+			patches: options.ControlPlaneConfigPatches,
+			fields:  []string{"spec", "controlPlaneConfig", "controlplane"},
+		},
+		{
+			kind:    "TalosConfigTemplate",
+			patches: options.WorkerConfigPatches,
+			fields:  []string{"spec", "template", "spec"},
+		},
+	} {
+		if len(target.patches) == 0 {
+			continue
+		}
+
+		var found bool
+
+		for i := range objs {
+			if objs[i].GetKind() != target.kind {
+				continue
+			}
+
+			found = true
+
+			if err := addTalosConfigPatches(&objs[i], target.patches, target.fields...); err != nil {
+				return err
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("cluster objects have no %s to apply the config patches to", target.kind)
+		}
+	}
+
+	return nil
+}
+
+func addTalosConfigPatches(obj *unstructured.Unstructured, patches []string, fields ...string) error {
+	configPatches, _, err := unstructured.NestedSlice(obj.Object, append(fields, "configPatches")...)
+	if err != nil {
+		return err
+	}
+
+	strategicPatches, _, err := unstructured.NestedStringSlice(obj.Object, append(fields, "strategicPatches")...)
+	if err != nil {
+		return err
+	}
+
+	for _, patch := range patches {
+		var (
+			loaded configpatcher.Patch
+			data   []byte
+		)
+
+		if loaded, err = configpatcher.LoadPatch([]byte(patch)); err != nil {
+			return err
+		}
+
+		if _, ok := loaded.(configpatcher.StrategicMergePatch); ok {
+			strategicPatches = append(strategicPatches, patch)
+
+			continue
+		}
+
+		if data, err = json.Marshal(loaded); err != nil {
+			return err
+		}
+
+		var operations []any
+
+		if err = json.Unmarshal(data, &operations); err != nil {
+			return err
+		}
+
+		configPatches = append(configPatches, operations...)
+	}
+
+	if len(configPatches) > 0 {
+		if err = unstructured.SetNestedSlice(obj.Object, configPatches, append(fields, "configPatches")...); err != nil {
+			return err
+		}
+	}
+
+	if len(strategicPatches) > 0 {
+		if err = unstructured.SetNestedStringSlice(obj.Object, strategicPatches, append(fields, "strategicPatches")...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
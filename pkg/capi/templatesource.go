@@ -0,0 +1,374 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+	orasretry "oras.land/oras-go/v2/registry/remote/retry"
+)
+
+const (
+	ociTemplateScheme = "oci://"
+	gitTemplateScheme = "git+"
+)
+
+// gitCommitRegexp matches SHA-1 and SHA-256 commit hashes.
+var gitCommitRegexp = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// FetchTemplate downloads the cluster template from the source.
+//
+// Supported sources:
+//   - local path or file:// URL;
+//   - http:// or https:// URL, GitHub blob URLs are fetched from raw.githubusercontent.com;
+//   - oci://registry/repository:tag or oci://registry/repository@sha256:..., optionally followed by //file.yaml
+//     to pick the artifact layer by its title, credentials are taken from the docker config;
+//   - git+https://host/repository.git//path/to/template.yaml?ref=v1.0.0 (also git+ssh:// and git+file://),
+//     ref defaults to HEAD and may be a branch, a tag or a commit.
+func FetchTemplate(ctx context.Context, source string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(source, ociTemplateScheme):
+		return fetchOCITemplate(ctx, strings.TrimPrefix(source, ociTemplateScheme))
+	case strings.HasPrefix(source, gitTemplateScheme):
+		return fetchGitTemplate(ctx, strings.TrimPrefix(source, gitTemplateScheme))
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		return fetchHTTPTemplate(ctx, source)
+	default:
+		return os.ReadFile(strings.TrimPrefix(source, "file://"))
+	}
+}
+
+// remoteTemplateSchemes are the template sources which do not read the local files.
+var remoteTemplateSchemes = []string{"http://", "https://", ociTemplateScheme, gitTemplateScheme + "https://", gitTemplateScheme + "ssh://"}
+
+// CheckRemoteTemplateSource rejects the template sources which read the local files,
+// it is used when the source comes from an untrusted spec.
+func CheckRemoteTemplateSource(source string) error {
+	for _, scheme := range remoteTemplateSchemes {
+		if strings.HasPrefix(source, scheme) {
+			return nil
+		}
+	}
+
+	return validationError(fmt.Errorf("template file %q should be a remote source, supported schemes: %s", source, strings.Join(remoteTemplateSchemes, ", ")))
+}
+
+// isRemoteTemplateSource returns true if the source is not supported by clusterctl directly.
+func isRemoteTemplateSource(source string) bool {
+	return strings.HasPrefix(source, ociTemplateScheme) || strings.HasPrefix(source, gitTemplateScheme)
+}
+
+func fetchHTTPTemplate(ctx context.Context, source string) ([]byte, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	// https://github.com/<owner>/<repository>/blob/<ref>/<path>
+	if u.Host == "github.com" {
+		if parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 4); len(parts) == 4 && parts[2] == "blob" {
+			u.Host = "raw.githubusercontent.com"
+			u.Path = "/" + path.Join(parts[0], parts[1], parts[3])
+		}
+	}
+
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the cluster template from %s %w", u, err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download the cluster template from %s got %d", u, resp.StatusCode)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func fetchOCITemplate(ctx context.Context, source string) ([]byte, error) {
+	reference, file, _ := strings.Cut(source, "//")
+
+	repo, err := remote.NewRepository(reference)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := credentials.NewStoreFromDocker(credentials.StoreOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	repo.Client = &auth.Client{
+		Client:     orasretry.DefaultClient,
+		Cache:      auth.NewCache(),
+		Credential: credentials.Credential(store),
+	}
+
+	_, manifestData, err := oras.FetchBytes(ctx, repo, repo.Reference.Reference, oras.DefaultFetchBytesOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the cluster template manifest %s: %w", reference, err)
+	}
+
+	var manifest ocispec.Manifest
+
+	if err = json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, err
+	}
+
+	var layer *ocispec.Descriptor
+
+	for i := range manifest.Layers {
+		title := manifest.Layers[i].Annotations[ocispec.AnnotationTitle]
+
+		if file == "" && len(manifest.Layers) == 1 || file != "" && title == file {
+			layer = &manifest.Layers[i]
+
+			break
+		}
+	}
+
+	if layer == nil {
+		if file == "" {
+			return nil, fmt.Errorf("artifact %s has %d layers, pick the template with %s//<file>", reference, len(manifest.Layers), reference)
+		}
+
+		return nil, fmt.Errorf("artifact %s has no layer titled %q", reference, file)
+	}
+
+	return content.FetchAll(ctx, repo, *layer)
+}
+
+func fetchGitTemplate(ctx context.Context, source string) ([]byte, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "https", "ssh", "file":
+	default:
+		return nil, validationError(fmt.Errorf("git template source %q uses unsupported transport %q, use https, ssh or file", source, u.Scheme))
+	}
+
+	if strings.HasPrefix(u.Host, "-") {
+		return nil, validationError(fmt.Errorf("git template source %q has invalid host %q", source, u.Host))
+	}
+
+	ref := u.Query().Get("ref")
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	if strings.HasPrefix(ref, "-") {
+		return nil, validationError(fmt.Errorf("git template source %q has invalid ref %q", source, ref))
+	}
+
+	repoPath, file, ok := strings.Cut(u.Path, "//")
+	if !ok || file == "" {
+		return nil, fmt.Errorf("git template source %q should contain the template path after //", source)
+	}
+
+	u.Path = repoPath
+	u.RawQuery = ""
+
+	dir, err := os.MkdirTemp("", "clusterTemplate")
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	git := func(args ...string) ([]byte, error) {
+		var stdout, stderr bytes.Buffer
+
+		cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+
+		if e := cmd.Run(); e != nil {
+			return nil, fmt.Errorf("git %s failed: %w: %s", args[0], e, strings.TrimSpace(stderr.String()))
+		}
+
+		return stdout.Bytes(), nil
+	}
+
+	if !gitCommitRegexp.MatchString(ref) {
+		if _, err = git("check-ref-format", "--allow-onelevel", ref); err != nil {
+			return nil, validationError(fmt.Errorf("git template source %q has invalid ref %q", source, ref))
+		}
+	}
+
+	if _, err = git("init", "-q"); err != nil {
+		return nil, err
+	}
+
+	if _, err = git("fetch", "-q", "--depth", "1", "--", u.String(), ref); err != nil {
+		return nil, err
+	}
+
+	return git("show", "FETCH_HEAD:"+file)
+}
+
+// TemplateCache stores cluster templates by their checksum.
+type TemplateCache struct {
+	dir string
+}
+
+// NewTemplateCache creates the cache in the directory.
+//
+// Empty dir means the default location in the user cache directory.
+func NewTemplateCache(dir string) (*TemplateCache, error) {
+	if dir == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+
+		dir = filepath.Join(cacheDir, "capi-utils", "templates")
+	}
+
+	return &TemplateCache{
+		dir: dir,
+	}, nil
+}
+
+// Get returns the cached template with the checksum.
+//
+// The cached data is verified against the checksum, corrupted entries are reported as missing.
+func (c *TemplateCache) Get(checksum digest.Digest) ([]byte, bool, error) {
+	data, err := os.ReadFile(c.path(checksum))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+
+		return nil, false, err
+	}
+
+	if checksum.Algorithm().FromBytes(data) != checksum {
+		return nil, false, nil
+	}
+
+	return data, true, nil
+}
+
+// Put stores the template in the cache and returns its checksum.
+func (c *TemplateCache) Put(data []byte) (digest.Digest, error) {
+	checksum := digest.FromBytes(data)
+
+	if err := os.MkdirAll(filepath.Dir(c.path(checksum)), 0o755); err != nil {
+		return "", err
+	}
+
+	file, err := os.CreateTemp(c.dir, ".template")
+	if err != nil {
+		return "", err
+	}
+
+	defer os.Remove(file.Name()) //nolint:errcheck
+
+	if _, err = file.Write(data); err != nil {
+		file.Close() //nolint:errcheck
+
+		return "", err
+	}
+
+	if err = file.Close(); err != nil {
+		return "", err
+	}
+
+	return checksum, os.Rename(file.Name(), c.path(checksum))
+}
+
+func (c *TemplateCache) path(checksum digest.Digest) string {
+	return filepath.Join(c.dir, checksum.Algorithm().String(), checksum.Encoded())
+}
+
+// resolveTemplate fetches the template into memory if the source is not supported by clusterctl
+// or the template is pinned by the checksum.
+func (options *DeployOptions) resolveTemplate(ctx context.Context) error {
+	if options.Template != nil || options.ClusterClass != "" || options.TemplateFile == "" {
+		return nil
+	}
+
+	if options.TemplateChecksum == "" && !isRemoteTemplateSource(options.TemplateFile) {
+		return nil
+	}
+
+	var (
+		cache *TemplateCache
+		err   error
+	)
+
+	if options.TemplateCache {
+		if cache, err = NewTemplateCache(options.TemplateCacheDir); err != nil {
+			return err
+		}
+	}
+
+	if cache != nil && options.TemplateChecksum != "" {
+		data, found, e := cache.Get(options.TemplateChecksum)
+		if e != nil {
+			return e
+		}
+
+		if found {
+			options.Template = data
+
+			return nil
+		}
+	}
+
+	data, err := FetchTemplate(ctx, options.TemplateFile)
+	if err != nil {
+		return err
+	}
+
+	if options.TemplateChecksum != "" {
+		if actual := options.TemplateChecksum.Algorithm().FromBytes(data); actual != options.TemplateChecksum {
+			return fmt.Errorf("cluster template %s checksum mismatch: expected %s, got %s", options.TemplateFile, options.TemplateChecksum, actual)
+		}
+	}
+
+	if cache != nil {
+		if _, err = cache.Put(data); err != nil {
+			return err
+		}
+	}
+
+	options.Template = data
+
+	return nil
+}
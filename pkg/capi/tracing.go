@@ -0,0 +1,47 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the OpenTelemetry tracer of the package.
+//
+// Spans are recorded with the global tracer provider, which is a no-op unless it is set by the caller.
+const TracerName = "github.com/siderolabs/capi-utils/pkg/capi"
+
+// Span attribute keys.
+const (
+	clusterNameKey      = attribute.Key("capi.cluster.name")
+	clusterNamespaceKey = attribute.Key("capi.cluster.namespace")
+	providerKey         = attribute.Key("capi.provider")
+)
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records the error of the operation and ends the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+func (cluster *Cluster) spanAttributes() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		clusterNameKey.String(cluster.name),
+		clusterNamespaceKey.String(cluster.namespace),
+	}
+}
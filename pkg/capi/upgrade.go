@@ -0,0 +1,127 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/siderolabs/go-retry/retry"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// UpgradeKubernetes upgrades the cluster to the Kubernetes version and waits until all machines are upgraded.
+//
+// Topology managed clusters are upgraded by patching the topology version,
+// otherwise the control plane is upgraded first, then the machine deployments and machine pools.
+func (cluster *Cluster) UpgradeKubernetes(ctx context.Context, version string) error {
+	version = capiKubernetesVersion(version)
+
+	if err := cluster.sync(ctx); err != nil {
+		return err
+	}
+
+	if cluster.TopologyManaged() {
+		if err := unstructured.SetNestedField(cluster.cluster.Object, version, "spec", "topology", "version"); err != nil {
+			return err
+		}
+
+		if err := cluster.manager.runtimeClient.Update(ctx, &cluster.cluster); err != nil {
+			return err
+		}
+
+		return cluster.waitKubernetesVersion(ctx, version, false)
+	}
+
+	controlPlane, err := cluster.ControlPlanes(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = unstructured.SetNestedField(controlPlane.Object, version, "spec", "version"); err != nil {
+		return err
+	}
+
+	if err = cluster.manager.runtimeClient.Update(ctx, controlPlane); err != nil {
+		return err
+	}
+
+	if err = cluster.waitKubernetesVersion(ctx, version, true); err != nil {
+		return err
+	}
+
+	machineDeployments, err := cluster.Workers(ctx)
+	if err != nil {
+		return err
+	}
+
+	machinePools, err := cluster.MachinePools(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range append(machineDeployments.Items, machinePools.Items...) {
+		if err = unstructured.SetNestedField(object.Object, version, "spec", "template", "spec", "version"); err != nil {
+			return err
+		}
+
+		if err = cluster.manager.runtimeClient.Update(ctx, &object); err != nil {
+			return err
+		}
+	}
+
+	return cluster.waitKubernetesVersion(ctx, version, false)
+}
+
+// waitKubernetesVersion waits until the machines are running the Kubernetes version and the cluster is healthy.
+func (cluster *Cluster) waitKubernetesVersion(ctx context.Context, version string, controlPlaneOnly bool) error {
+	// give controllers some time to notice the new version
+	time.Sleep(2 * time.Second)
+
+	err := retry.Constant(60*time.Minute, retry.WithUnits(10*time.Second)).Retry(logRetries(cluster.logger(), func() error {
+		machines, err := cluster.Machines(ctx)
+		if err != nil {
+			return err
+		}
+
+		var machineVersion string
+
+		for _, machine := range machines.Items {
+			if _, controlPlane := machine.GetLabels()[clusterv1.MachineControlPlaneLabel]; controlPlaneOnly && !controlPlane {
+				continue
+			}
+
+			if machineVersion, _, err = unstructured.NestedString(machine.Object, "spec", "version"); err != nil {
+				return err
+			}
+
+			if machineVersion != version {
+				return retry.ExpectedErrorf("machine %s version is %s, expected %s", machine.GetName(), machineVersion, version)
+			}
+		}
+
+		return cluster.manager.CheckClusterReady(ctx, cluster)
+	}))
+	if err != nil {
+		return err
+	}
+
+	if err = cluster.Sync(ctx); err != nil {
+		return err
+	}
+
+	return cluster.Health(ctx)
+}
+
+// capiKubernetesVersion adds the "v" prefix to the Kubernetes version as CAPI expects it.
+func capiKubernetesVersion(version string) string {
+	if strings.HasPrefix(version, "v") {
+		return version
+	}
+
+	return "v" + version
+}
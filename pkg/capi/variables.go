@@ -0,0 +1,172 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+
+	"github.com/siderolabs/capi-utils/pkg/capi/infrastructure"
+)
+
+// MissingVariablesError lists the template variables which have no default and are not set.
+type MissingVariablesError struct {
+	// Missing variables are not set at all.
+	Missing []string
+	// Empty variables are set to an empty string.
+	Empty []string
+}
+
+// Error implements error interface.
+func (e *MissingVariablesError) Error() string {
+	var parts []string
+
+	if len(e.Missing) > 0 {
+		parts = append(parts, fmt.Sprintf("missing: %s", strings.Join(e.Missing, ", ")))
+	}
+
+	if len(e.Empty) > 0 {
+		parts = append(parts, fmt.Sprintf("empty: %s", strings.Join(e.Empty, ", ")))
+	}
+
+	return fmt.Sprintf(
+		"cluster template variables are not set (%s), set them using os env variables or the clusterctl config file",
+		strings.Join(parts, "; "),
+	)
+}
+
+// CheckTemplateVariables verifies that all variables required by the cluster template are set
+// without deploying anything.
+//
+// It returns the sorted list of all variables used by the template.
+// If any of them have no default and are not set, *MissingVariablesError is returned.
+func (clusterAPI *Manager) CheckTemplateVariables(ctx context.Context, clusterName string, setters ...DeployOption) ([]string, error) {
+	options, provider, err := clusterAPI.deployOptions(ctx, clusterName, setters...)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.ClusterClass != "" {
+		return nil, validationError(fmt.Errorf("ClusterClass based clusters do not use the cluster template"))
+	}
+
+	render, err := clusterAPI.newTemplateRender(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	templateOptions, vars, cleanup, err := render.templateOptions(clusterAPI, provider, options)
+	if err != nil {
+		return nil, err
+	}
+
+	defer cleanup()
+
+	variables, err := render.templateVariables(provider, templateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.Sorted(maps.Keys(variables)), render.checkVariables(variables, vars)
+}
+
+// templateRender holds the config and the clusterctl client of a single template render.
+//
+// The template variables are set on a copy of the manager config, so the renders do not affect each other.
+type templateRender struct {
+	client client.Client
+	cfg    *Config
+}
+
+func (clusterAPI *Manager) newTemplateRender(ctx context.Context) (*templateRender, error) {
+	cfg, err := clusterAPI.cfg.clone()
+	if err != nil {
+		return nil, err
+	}
+
+	renderClient, err := clusterAPI.newClusterctlClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &templateRender{
+		client: renderClient,
+		cfg:    cfg,
+	}, nil
+}
+
+func (render *templateRender) patchConfig(vars infrastructure.Variables) {
+	for key, value := range vars {
+		if value != "" {
+			render.cfg.Set(key, value)
+		}
+	}
+}
+
+func (render *templateRender) checkTemplateVariables(
+	provider infrastructure.Provider,
+	templateOptions client.GetClusterTemplateOptions,
+	vars infrastructure.Variables,
+) error {
+	variables, err := render.templateVariables(provider, templateOptions)
+	if err != nil {
+		return err
+	}
+
+	return render.checkVariables(variables, vars)
+}
+
+// templateVariables returns the variables used by the template with their default values.
+func (render *templateRender) templateVariables(
+	provider infrastructure.Provider,
+	templateOptions client.GetClusterTemplateOptions,
+) (map[string]*string, error) {
+	templateOptions.ListVariablesOnly = true
+
+	template, err := provider.GetClusterTemplate(render.client, templateOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return template.VariableMap(), nil
+}
+
+// checkVariables checks the variables without defaults against the config and the provider cluster variables.
+func (render *templateRender) checkVariables(variables map[string]*string, vars infrastructure.Variables) error {
+	var res MissingVariablesError
+
+	for _, name := range slices.Sorted(maps.Keys(variables)) {
+		if variables[name] != nil {
+			continue
+		}
+
+		value, err := render.cfg.Get(name)
+		if err != nil {
+			// the provider knows the variable but the deploy options leave it empty
+			if _, ok := vars[name]; ok {
+				res.Empty = append(res.Empty, name)
+			} else {
+				res.Missing = append(res.Missing, name)
+			}
+
+			continue
+		}
+
+		if value == "" {
+			res.Empty = append(res.Empty, name)
+		}
+	}
+
+	if len(res.Missing) > 0 || len(res.Empty) > 0 {
+		return &res
+	}
+
+	return nil
+}
@@ -0,0 +1,262 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package capi
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	"github.com/siderolabs/capi-utils/pkg/capi/infrastructure"
+)
+
+// WorkerPool defines a named group of worker nodes.
+type WorkerPool struct {
+	// ProviderOptions are provider specific deploy options used for the pool machines,
+	// e.g. *infrastructure.AWSDeployOptions with the pool machine type.
+	//
+	// Defaults to the cluster provider options.
+	ProviderOptions any
+	// Labels are set on the pool MachineDeployment and the pool nodes.
+	Labels map[string]string
+	Name   string
+	// Taints are set on the pool nodes.
+	Taints   []corev1.Taint
+	Replicas int64
+}
+
+func (pool *WorkerPool) validate() error {
+	if errs := validation.IsDNS1123Label(pool.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid worker pool name %q: %v", pool.Name, errs)
+	}
+
+	if pool.Replicas < 0 {
+		return fmt.Errorf("invalid worker pool %q replicas count %d", pool.Name, pool.Replicas)
+	}
+
+	return nil
+}
+
+// renderWorkerPools generates cluster objects from the cluster template replacing template workers
+// with a MachineDeployment per worker pool.
+//
+// Each pool is rendered with a fresh config, so the variables of one pool do not leak into the others.
+// The template should have a single MachineDeployment.
+func (clusterAPI *Manager) renderWorkerPools(ctx context.Context, provider infrastructure.Provider, options *DeployOptions) ([]unstructured.Unstructured, error) {
+	objs, err := clusterAPI.renderTemplate(ctx, provider, options)
+	if err != nil {
+		return nil, err
+	}
+
+	workers, err := findWorkerObjects(objs)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]unstructured.Unstructured, 0, len(objs))
+
+	for _, obj := range objs {
+		if !workers.contains(&obj) {
+			res = append(res, obj)
+		}
+	}
+
+	for _, pool := range options.WorkerPools {
+		poolOptions := *options
+		poolOptions.WorkerNodes = pool.Replicas
+
+		if pool.ProviderOptions != nil {
+			poolOptions.providerOptions = pool.ProviderOptions
+		}
+
+		if objs, err = clusterAPI.renderTemplate(ctx, provider, &poolOptions); err != nil {
+			return nil, err
+		}
+
+		if workers, err = findWorkerObjects(objs); err != nil {
+			return nil, err
+		}
+
+		if err = workers.applyPool(options.ClusterName, &pool); err != nil {
+			return nil, err
+		}
+
+		res = append(res, *workers.machineDeployment, *workers.infrastructureTemplate, *workers.bootstrapTemplate)
+	}
+
+	return res, nil
+}
+
+// workerObjects is a MachineDeployment with the templates it references.
+type workerObjects struct {
+	machineDeployment      *unstructured.Unstructured
+	infrastructureTemplate *unstructured.Unstructured
+	bootstrapTemplate      *unstructured.Unstructured
+}
+
+func findWorkerObjects(objs []unstructured.Unstructured) (*workerObjects, error) {
+	res := &workerObjects{}
+
+	for i := range objs {
+		if objs[i].GetKind() != "MachineDeployment" {
+			continue
+		}
+
+		// the other machine deployments would stay next to the pools as undeclared worker groups
+		if res.machineDeployment != nil {
+			return nil, validationError(fmt.Errorf("worker pools require the cluster template with a single machine deployment, found %s and %s",
+				res.machineDeployment.GetName(), objs[i].GetName()))
+		}
+
+		res.machineDeployment = &objs[i]
+	}
+
+	if res.machineDeployment == nil {
+		return nil, fmt.Errorf("cluster template has no machine deployments")
+	}
+
+	for _, r := range []struct {
+		dest   **unstructured.Unstructured
+		fields []string
+	}{
+		{&res.infrastructureTemplate, []string{"spec", "template", "spec", "infrastructureRef"}},
+		{&res.bootstrapTemplate, []string{"spec", "template", "spec", "bootstrap", "configRef"}},
+	} {
+		kind, _, err := unstructured.NestedString(res.machineDeployment.Object, append(r.fields, "kind")...)
+		if err != nil {
+			return nil, err
+		}
+
+		name, _, err := unstructured.NestedString(res.machineDeployment.Object, append(r.fields, "name")...)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range objs {
+			if objs[i].GetKind() == kind && objs[i].GetName() == name {
+				*r.dest = &objs[i]
+
+				break
+			}
+		}
+
+		if *r.dest == nil {
+			return nil, fieldNotFound(r.fields...)
+		}
+	}
+
+	return res, nil
+}
+
+func (w *workerObjects) contains(obj *unstructured.Unstructured) bool {
+	for _, o := range []*unstructured.Unstructured{w.machineDeployment, w.infrastructureTemplate, w.bootstrapTemplate} {
+		if o.GetKind() == obj.GetKind() && o.GetName() == obj.GetName() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyPool renames worker objects after the pool and applies pool replicas, labels and taints.
+func (w *workerObjects) applyPool(clusterName string, pool *WorkerPool) error {
+	name := clusterName + "-" + pool.Name
+
+	w.machineDeployment.SetName(name)
+	w.infrastructureTemplate.SetName(name)
+	w.bootstrapTemplate.SetName(name)
+
+	if err := unstructured.SetNestedField(w.machineDeployment.Object, name, "spec", "template", "spec", "infrastructureRef", "name"); err != nil {
+		return err
+	}
+
+	if err := unstructured.SetNestedField(w.machineDeployment.Object, name, "spec", "template", "spec", "bootstrap", "configRef", "name"); err != nil {
+		return err
+	}
+
+	if err := unstructured.SetNestedField(w.machineDeployment.Object, pool.Replicas, "spec", "replicas"); err != nil {
+		return err
+	}
+
+	if _, found, _ := unstructured.NestedString(w.machineDeployment.Object, "spec", "selector", "matchLabels", clusterv1.MachineDeploymentNameLabel); found { //nolint:errcheck
+		if err := unstructured.SetNestedField(w.machineDeployment.Object, name, "spec", "selector", "matchLabels", clusterv1.MachineDeploymentNameLabel); err != nil {
+			return err
+		}
+	}
+
+	if _, found, _ := unstructured.NestedString(w.machineDeployment.Object, "spec", "template", "metadata", "labels", clusterv1.MachineDeploymentNameLabel); found { //nolint:errcheck
+		if err := unstructured.SetNestedField(w.machineDeployment.Object, name, "spec", "template", "metadata", "labels", clusterv1.MachineDeploymentNameLabel); err != nil {
+			return err
+		}
+	}
+
+	if len(pool.Labels) > 0 {
+		labels := w.machineDeployment.GetLabels()
+		if labels == nil {
+			labels = map[string]string{}
+		}
+
+		maps.Copy(labels, pool.Labels)
+
+		w.machineDeployment.SetLabels(labels)
+	}
+
+	return w.patchNodeMetadata(pool)
+}
+
+// patchNodeMetadata adds Talos config patches setting the pool node labels and taints.
+func (w *workerObjects) patchNodeMetadata(pool *WorkerPool) error {
+	if len(pool.Labels) == 0 && len(pool.Taints) == 0 {
+		return nil
+	}
+
+	if w.bootstrapTemplate.GetKind() != "TalosConfigTemplate" {
+		return fmt.Errorf("worker pool labels and taints require TalosConfigTemplate, got %s", w.bootstrapTemplate.GetKind())
+	}
+
+	patches, _, err := unstructured.NestedSlice(w.bootstrapTemplate.Object, "spec", "template", "spec", "configPatches")
+	if err != nil {
+		return err
+	}
+
+	if len(pool.Labels) > 0 {
+		nodeLabels := make(map[string]any, len(pool.Labels))
+
+		for k, v := range pool.Labels {
+			nodeLabels[k] = v
+		}
+
+		patches = append(patches, map[string]any{
+			"op":    "add",
+			"path":  "/machine/nodeLabels",
+			"value": nodeLabels,
+		})
+	}
+
+	if len(pool.Taints) > 0 {
+		nodeTaints := make(map[string]any, len(pool.Taints))
+
+		for _, taint := range pool.Taints {
+			if taint.Value == "" {
+				nodeTaints[taint.Key] = string(taint.Effect)
+			} else {
+				nodeTaints[taint.Key] = fmt.Sprintf("%s:%s", taint.Value, taint.Effect)
+			}
+		}
+
+		patches = append(patches, map[string]any{
+			"op":    "add",
+			"path":  "/machine/nodeTaints",
+			"value": nodeTaints,
+		})
+	}
+
+	return unstructured.SetNestedSlice(w.bootstrapTemplate.Object, patches, "spec", "template", "spec", "configPatches")
+}
@@ -15,4 +15,22 @@ const (
 	AWSProviderName = "aws"
 	// AWSCAPANamespace default AWS provider CAPI system namespace.
 	AWSCAPANamespace = "capa-system"
+
+	// ManagedByLabel marks the objects created by capi-utils.
+	ManagedByLabel = "app.kubernetes.io/managed-by"
+	// ManagedByValue is the value of ManagedByLabel.
+	ManagedByValue = "capi-utils"
+	// ExpiresAtAnnotation holds the RFC3339 deadline after which the cluster is garbage collected.
+	ExpiresAtAnnotation = "capi-utils.siderolabs.dev/expires-at"
+
+	// PoolProfileLabel holds the pool profile of the pooled cluster.
+	PoolProfileLabel = "capi-utils.siderolabs.dev/pool-profile"
+	// PoolReadyAnnotation marks the pooled cluster as deployed and ready to be leased.
+	PoolReadyAnnotation = "capi-utils.siderolabs.dev/pool-ready"
+	// LeaseHolderAnnotation holds the identity of the pooled cluster lease holder.
+	LeaseHolderAnnotation = "capi-utils.siderolabs.dev/lease-holder"
+	// LeaseExpiresAtAnnotation holds the RFC3339 deadline of the pooled cluster lease.
+	LeaseExpiresAtAnnotation = "capi-utils.siderolabs.dev/lease-expires-at"
+	// PausedByAnnotation marks the objects paused by capi-utils, only they are resumed.
+	PausedByAnnotation = "capi-utils.siderolabs.dev/paused"
 )
@@ -0,0 +1,42 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package controller
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/siderolabs/capi-utils/pkg/controller/v1alpha1"
+)
+
+// InstallCRDs creates or updates the controller CustomResourceDefinitions.
+func InstallCRDs(ctx context.Context, client runtimeclient.Client) error {
+	var crd unstructured.Unstructured
+
+	if err := yaml.Unmarshal(v1alpha1.TestClusterCRD, &crd.Object); err != nil {
+		return err
+	}
+
+	err := client.Create(ctx, &crd)
+	if !apierrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	var existing unstructured.Unstructured
+
+	existing.SetGroupVersionKind(crd.GroupVersionKind())
+
+	if err = client.Get(ctx, runtimeclient.ObjectKeyFromObject(&crd), &existing); err != nil {
+		return err
+	}
+
+	crd.SetResourceVersion(existing.GetResourceVersion())
+
+	return client.Update(ctx, &crd)
+}
@@ -0,0 +1,263 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package controller implements controller mode of capi-utils.
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/siderolabs/capi-utils/pkg/capi"
+	"github.com/siderolabs/capi-utils/pkg/controller/v1alpha1"
+)
+
+// TestClusterReconciler deploys, scales, checks and destroys the CAPI clusters described by TestClusters.
+type TestClusterReconciler struct {
+	Client  runtimeclient.Client
+	Manager *capi.Manager
+	// RequeueInterval is the interval of the cluster health checks.
+	RequeueInterval time.Duration
+}
+
+// SetupWithManager registers the reconciler in the controller manager.
+//
+// Deploy and scale operations block the reconcile loop until the cluster is ready,
+// so concurrency limits the number of clusters processed at once.
+func (r *TestClusterReconciler) SetupWithManager(mgr ctrl.Manager, concurrency int) error {
+	if r.RequeueInterval == 0 {
+		r.RequeueInterval = time.Minute
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.TestCluster{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: concurrency}).
+		Complete(r)
+}
+
+// Reconcile implements reconcile.Reconciler.
+func (r *TestClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var testCluster v1alpha1.TestCluster
+
+	if err := r.Client.Get(ctx, req.NamespacedName, &testCluster); err != nil {
+		return ctrl.Result{}, runtimeclient.IgnoreNotFound(err)
+	}
+
+	if !testCluster.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileDelete(ctx, &testCluster)
+	}
+
+	if controllerutil.AddFinalizer(&testCluster, v1alpha1.TestClusterFinalizer) {
+		if err := r.Client.Update(ctx, &testCluster); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	requeueAfter := r.RequeueInterval
+
+	if testCluster.Spec.TTL != nil {
+		expiresAt := testCluster.CreationTimestamp.Add(testCluster.Spec.TTL.Duration)
+
+		if !time.Now().Before(expiresAt) {
+			return ctrl.Result{}, runtimeclient.IgnoreNotFound(r.Client.Delete(ctx, &testCluster))
+		}
+
+		testCluster.Status.ExpiresAt = &metav1.Time{Time: expiresAt}
+		requeueAfter = min(requeueAfter, time.Until(expiresAt))
+	}
+
+	reconcileErr := r.reconcile(ctx, &testCluster)
+
+	testCluster.Status.ObservedGeneration = testCluster.Generation
+
+	if err := r.Client.Status().Update(ctx, &testCluster); err != nil {
+		return ctrl.Result{}, errors.Join(reconcileErr, err)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, reconcileErr
+}
+
+func (r *TestClusterReconciler) reconcile(ctx context.Context, testCluster *v1alpha1.TestCluster) error {
+	exists, owned, err := r.clusterOwnership(ctx, testCluster)
+	if err != nil {
+		return err
+	}
+
+	// the cluster with the same name was not deployed for this TestCluster, it is left as is
+	if exists && !owned {
+		err = fmt.Errorf("cluster %s/%s is not deployed by this TestCluster", testCluster.Namespace, testCluster.Name)
+
+		setCondition(testCluster, v1alpha1.DeployedCondition, metav1.ConditionFalse, "NotOwned", err)
+		setCondition(testCluster, v1alpha1.ReadyCondition, metav1.ConditionFalse, "NotOwned", err)
+
+		return nil
+	}
+
+	cluster, err := r.Manager.NewCluster(ctx, testCluster.Name, testCluster.Namespace)
+	if apierrors.IsNotFound(err) {
+		cluster, err = r.deploy(ctx, testCluster)
+	}
+
+	if err != nil {
+		setCondition(testCluster, v1alpha1.ReadyCondition, metav1.ConditionFalse, "DeployFailed", err)
+
+		return err
+	}
+
+	setCondition(testCluster, v1alpha1.DeployedCondition, metav1.ConditionTrue, "Deployed", nil)
+
+	if err = cluster.Scale(ctx, int(testCluster.Spec.ControlPlaneNodes), capi.ControlPlaneNodes); err == nil {
+		err = cluster.Scale(ctx, int(testCluster.Spec.WorkerNodes), capi.WorkerNodes)
+	}
+
+	if err != nil {
+		setCondition(testCluster, v1alpha1.ScaledCondition, metav1.ConditionFalse, "ScaleFailed", err)
+		setCondition(testCluster, v1alpha1.ReadyCondition, metav1.ConditionFalse, "ScaleFailed", err)
+
+		return err
+	}
+
+	setCondition(testCluster, v1alpha1.ScaledCondition, metav1.ConditionTrue, "Scaled", nil)
+
+	if err = cluster.Health(ctx); err != nil {
+		setCondition(testCluster, v1alpha1.ReadyCondition, metav1.ConditionFalse, "Unhealthy", err)
+
+		// unhealthy cluster is not a reconcile failure, it is checked again after the requeue interval
+		return nil
+	}
+
+	setCondition(testCluster, v1alpha1.ReadyCondition, metav1.ConditionTrue, "Healthy", nil)
+
+	return nil
+}
+
+func (r *TestClusterReconciler) deploy(ctx context.Context, testCluster *v1alpha1.TestCluster) (*capi.Cluster, error) {
+	setCondition(testCluster, v1alpha1.DeployedCondition, metav1.ConditionFalse, "Provisioning", nil)
+	setCondition(testCluster, v1alpha1.ReadyCondition, metav1.ConditionFalse, "Provisioning", nil)
+
+	// report provisioning as DeployCluster blocks until the cluster is ready
+	if err := r.Client.Status().Update(ctx, testCluster); err != nil {
+		return nil, err
+	}
+
+	spec := testCluster.Spec
+
+	// the controller must not read its own files on behalf of the TestCluster authors
+	if spec.TemplateFile != "" {
+		if err := capi.CheckRemoteTemplateSource(spec.TemplateFile); err != nil {
+			setCondition(testCluster, v1alpha1.DeployedCondition, metav1.ConditionFalse, "InvalidSpec", err)
+
+			return nil, err
+		}
+	}
+
+	opts := []capi.DeployOption{
+		capi.WithClusterNamespace(testCluster.Namespace),
+		capi.WithControlPlaneNodes(spec.ControlPlaneNodes),
+		capi.WithWorkerNodes(spec.WorkerNodes),
+		capi.WithProvider(spec.Provider),
+		capi.WithProviderVersion(spec.ProviderVersion),
+		capi.WithTemplateFile(spec.TemplateFile),
+		// DeployCluster also stamps the managed-by label, both are required to adopt the cluster
+		capi.WithLabels(map[string]string{
+			v1alpha1.TestClusterUIDLabel: string(testCluster.UID),
+		}),
+	}
+
+	if spec.TalosVersion != "" {
+		opts = append(opts, capi.WithTalosVersion(spec.TalosVersion))
+	}
+
+	if spec.KubernetesVersion != "" {
+		opts = append(opts, capi.WithKubernetesVersion(spec.KubernetesVersion))
+	}
+
+	cluster, err := r.Manager.DeployCluster(ctx, testCluster.Name, opts...)
+	if err != nil {
+		setCondition(testCluster, v1alpha1.DeployedCondition, metav1.ConditionFalse, "DeployFailed", err)
+
+		return nil, err
+	}
+
+	return cluster, nil
+}
+
+func (r *TestClusterReconciler) reconcileDelete(ctx context.Context, testCluster *v1alpha1.TestCluster) error {
+	if !controllerutil.ContainsFinalizer(testCluster, v1alpha1.TestClusterFinalizer) {
+		return nil
+	}
+
+	setCondition(testCluster, v1alpha1.ReadyCondition, metav1.ConditionFalse, "Deleting", nil)
+
+	if err := r.Client.Status().Update(ctx, testCluster); err != nil {
+		return err
+	}
+
+	_, owned, err := r.clusterOwnership(ctx, testCluster)
+	if err != nil {
+		return err
+	}
+
+	// the cluster not deployed for this TestCluster is not destroyed,
+	// the selector protects from the cluster replaced since the check
+	if owned {
+		if err = r.Manager.DestroyCluster(ctx, testCluster.Name, testCluster.Namespace, capi.WithDestroySelector(ownerSelector(testCluster))); err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(testCluster, v1alpha1.TestClusterFinalizer)
+
+	return r.Client.Update(ctx, testCluster)
+}
+
+// ownerSelector matches the CAPI cluster deployed for the TestCluster.
+func ownerSelector(testCluster *v1alpha1.TestCluster) labels.Selector {
+	return capi.ManagedSelector(map[string]string{
+		v1alpha1.TestClusterUIDLabel: string(testCluster.UID),
+	})
+}
+
+// clusterOwnership checks if the CAPI cluster with the TestCluster name exists and was deployed for the TestCluster.
+func (r *TestClusterReconciler) clusterOwnership(ctx context.Context, testCluster *v1alpha1.TestCluster) (exists, owned bool, err error) {
+	clusters, err := r.Manager.ListClusters(ctx, testCluster.Namespace, nil)
+	if err != nil {
+		return false, false, err
+	}
+
+	for _, cluster := range clusters.Items {
+		if cluster.GetName() == testCluster.Name {
+			return true, ownerSelector(testCluster).Matches(labels.Set(cluster.GetLabels())), nil
+		}
+	}
+
+	return false, false, nil
+}
+
+func setCondition(testCluster *v1alpha1.TestCluster, conditionType string, status metav1.ConditionStatus, reason string, err error) {
+	var message string
+
+	if err != nil {
+		message = err.Error()
+	}
+
+	meta.SetStatusCondition(&testCluster.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: testCluster.Generation,
+	})
+}
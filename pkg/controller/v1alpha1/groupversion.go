@@ -0,0 +1,33 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package v1alpha1 contains capi-utils controller API types.
+package v1alpha1
+
+import (
+	_ "embed"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is the group version of the capi-utils controller API.
+	GroupVersion = schema.GroupVersion{Group: "capi-utils.siderolabs.dev", Version: "v1alpha1"}
+
+	// SchemeBuilder registers the API types.
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the API types to the scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+
+	// TestClusterCRD is the TestCluster CustomResourceDefinition manifest.
+	//
+	//go:embed testcluster.yaml
+	TestClusterCRD []byte
+)
+
+func init() {
+	SchemeBuilder.Register(&TestCluster{}, &TestClusterList{})
+}
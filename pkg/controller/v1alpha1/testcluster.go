@@ -0,0 +1,151 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// ReadyCondition reports that the cluster is deployed and healthy.
+	ReadyCondition = "Ready"
+	// DeployedCondition reports that the CAPI cluster is deployed.
+	DeployedCondition = "Deployed"
+	// ScaledCondition reports that the cluster replicas match the spec.
+	ScaledCondition = "Scaled"
+
+	// TestClusterFinalizer destroys the CAPI cluster when the TestCluster is deleted.
+	TestClusterFinalizer = "capi-utils.siderolabs.dev/testcluster"
+	// TestClusterUIDLabel holds the UID of the TestCluster which deployed the CAPI cluster.
+	TestClusterUIDLabel = "capi-utils.siderolabs.dev/testcluster-uid"
+)
+
+// TestClusterSpec defines the desired Talos CAPI cluster.
+//
+// The CAPI cluster has the same name and namespace as the TestCluster.
+type TestClusterSpec struct {
+	// TTL deletes the TestCluster after the duration since its creation.
+	TTL               *metav1.Duration `json:"ttl,omitempty"`
+	Provider          string           `json:"provider,omitempty"`
+	ProviderVersion   string           `json:"providerVersion,omitempty"`
+	TalosVersion      string           `json:"talosVersion,omitempty"`
+	KubernetesVersion string           `json:"kubernetesVersion,omitempty"`
+	// TemplateFile is the cluster template source, see capi.WithTemplateFile.
+	//
+	// Only remote sources are accepted, see capi.CheckRemoteTemplateSource.
+	TemplateFile      string `json:"templateFile,omitempty"`
+	ControlPlaneNodes int64  `json:"controlPlaneNodes"`
+	WorkerNodes       int64  `json:"workerNodes"`
+}
+
+// TestClusterStatus defines the observed state of the TestCluster.
+type TestClusterStatus struct {
+	ExpiresAt          *metav1.Time       `json:"expiresAt,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+}
+
+// TestCluster describes a Talos CAPI cluster reconciled by the capi-utils controller.
+type TestCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TestClusterSpec   `json:"spec,omitempty"`
+	Status TestClusterStatus `json:"status,omitempty"`
+}
+
+// TestClusterList is a list of TestClusters.
+type TestClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []TestCluster `json:"items"`
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *TestClusterSpec) DeepCopyInto(out *TestClusterSpec) {
+	*out = *in
+
+	if in.TTL != nil {
+		out.TTL = new(metav1.Duration)
+		*out.TTL = *in.TTL
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *TestClusterStatus) DeepCopyInto(out *TestClusterStatus) {
+	*out = *in
+
+	if in.ExpiresAt != nil {
+		out.ExpiresAt = in.ExpiresAt.DeepCopy()
+	}
+
+	if in.Conditions != nil {
+		out.Conditions = make([]metav1.Condition, len(in.Conditions))
+
+		for i := range in.Conditions {
+			in.Conditions[i].DeepCopyInto(&out.Conditions[i])
+		}
+	}
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *TestCluster) DeepCopyInto(out *TestCluster) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy copies the TestCluster.
+func (in *TestCluster) DeepCopy() *TestCluster {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TestCluster)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *TestCluster) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto copies the receiver into out.
+func (in *TestClusterList) DeepCopyInto(out *TestClusterList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+
+	if in.Items != nil {
+		out.Items = make([]TestCluster, len(in.Items))
+
+		for i := range in.Items {
+			in.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+}
+
+// DeepCopy copies the TestClusterList.
+func (in *TestClusterList) DeepCopy() *TestClusterList {
+	if in == nil {
+		return nil
+	}
+
+	out := new(TestClusterList)
+	in.DeepCopyInto(out)
+
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *TestClusterList) DeepCopyObject() runtime.Object {
+	return in.DeepCopy()
+}
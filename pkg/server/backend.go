@@ -0,0 +1,229 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/siderolabs/capi-utils/pkg/capi"
+	"github.com/siderolabs/capi-utils/pkg/constants"
+)
+
+// Backend runs the cluster operations for the server.
+//
+// ManagerBackend implements it on top of the CAPI manager,
+// tests can use a fake implementation.
+type Backend interface {
+	ListClusters(ctx context.Context, namespace string, selector labels.Selector) ([]ClusterStatus, error)
+	GetCluster(ctx context.Context, name, namespace string) (*ClusterStatus, error)
+	CreateCluster(ctx context.Context, spec *capi.ClusterSpec) error
+	ScaleCluster(ctx context.Context, name, namespace string, request ScaleRequest) error
+	DeleteCluster(ctx context.Context, name, namespace string, selector labels.Selector) error
+	Kubeconfig(ctx context.Context, name, namespace string) ([]byte, error)
+	Talosconfig(ctx context.Context, name, namespace string) ([]byte, error)
+	Health(ctx context.Context, name, namespace string) error
+}
+
+// ClusterStatus is the cluster state reported by the API.
+type ClusterStatus struct {
+	CreatedAt           time.Time          `json:"createdAt"`
+	ExpiresAt           *time.Time         `json:"expiresAt,omitempty"`
+	Labels              map[string]string  `json:"labels,omitempty"`
+	Annotations         map[string]string  `json:"annotations,omitempty"`
+	Name                string             `json:"name"`
+	Namespace           string             `json:"namespace"`
+	Phase               string             `json:"phase,omitempty"`
+	Conditions          []ClusterCondition `json:"conditions,omitempty"`
+	ControlPlaneReady   bool               `json:"controlPlaneReady"`
+	InfrastructureReady bool               `json:"infrastructureReady"`
+}
+
+// ClusterCondition is a single condition of the Cluster object.
+type ClusterCondition struct {
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// ScaleRequest sets the number of the cluster nodes, nil fields are left as is.
+type ScaleRequest struct {
+	ControlPlaneNodes *int `json:"controlPlaneNodes,omitempty"`
+	WorkerNodes       *int `json:"workerNodes,omitempty"`
+}
+
+// ManagerBackend runs the cluster operations using the CAPI manager.
+type ManagerBackend struct {
+	Manager *capi.Manager
+}
+
+// ListClusters implements Backend.
+func (b *ManagerBackend) ListClusters(ctx context.Context, namespace string, selector labels.Selector) ([]ClusterStatus, error) {
+	clusters, err := b.Manager.ListClusters(ctx, namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]ClusterStatus, 0, len(clusters.Items))
+
+	for i := range clusters.Items {
+		res = append(res, clusterStatus(&clusters.Items[i]))
+	}
+
+	return res, nil
+}
+
+// GetCluster implements Backend.
+func (b *ManagerBackend) GetCluster(ctx context.Context, name, namespace string) (*ClusterStatus, error) {
+	client, err := b.Manager.GetClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var cluster unstructured.Unstructured
+
+	cluster.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "cluster.x-k8s.io",
+		Kind:    "Cluster",
+		Version: b.Manager.Version(),
+	})
+
+	if err = client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &cluster); err != nil {
+		return nil, err
+	}
+
+	status := clusterStatus(&cluster)
+
+	return &status, nil
+}
+
+// CreateCluster implements Backend.
+//
+// It deploys the cluster and waits for it to become healthy.
+func (b *ManagerBackend) CreateCluster(ctx context.Context, spec *capi.ClusterSpec) error {
+	cluster, err := b.Manager.DeployCluster(ctx, spec.Name, capi.WithClusterSpec(spec))
+	if err != nil {
+		return err
+	}
+
+	return cluster.Health(ctx)
+}
+
+// ScaleCluster implements Backend.
+func (b *ManagerBackend) ScaleCluster(ctx context.Context, name, namespace string, request ScaleRequest) error {
+	cluster, err := b.Manager.NewCluster(ctx, name, namespace)
+	if err != nil {
+		return err
+	}
+
+	if request.ControlPlaneNodes != nil {
+		if err = cluster.Scale(ctx, *request.ControlPlaneNodes, capi.ControlPlaneNodes); err != nil {
+			return err
+		}
+	}
+
+	if request.WorkerNodes != nil {
+		if err = cluster.Scale(ctx, *request.WorkerNodes, capi.WorkerNodes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteCluster implements Backend.
+func (b *ManagerBackend) DeleteCluster(ctx context.Context, name, namespace string, selector labels.Selector) error {
+	return b.Manager.DestroyCluster(ctx, name, namespace, capi.WithDestroySelector(selector))
+}
+
+// Kubeconfig implements Backend.
+func (b *ManagerBackend) Kubeconfig(ctx context.Context, name, namespace string) ([]byte, error) {
+	cluster, err := b.Manager.NewCluster(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeconfig, err := cluster.Kubeconfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(kubeconfig), nil
+}
+
+// Talosconfig implements Backend.
+func (b *ManagerBackend) Talosconfig(ctx context.Context, name, namespace string) ([]byte, error) {
+	cluster, err := b.Manager.NewCluster(ctx, name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := cluster.TalosConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.Bytes()
+}
+
+// Health implements Backend.
+func (b *ManagerBackend) Health(ctx context.Context, name, namespace string) error {
+	cluster, err := b.Manager.NewCluster(ctx, name, namespace)
+	if err != nil {
+		return err
+	}
+
+	return cluster.Health(ctx)
+}
+
+func clusterStatus(cluster *unstructured.Unstructured) ClusterStatus {
+	status := ClusterStatus{
+		Name:        cluster.GetName(),
+		Namespace:   cluster.GetNamespace(),
+		CreatedAt:   cluster.GetCreationTimestamp().Time,
+		Labels:      cluster.GetLabels(),
+		Annotations: cluster.GetAnnotations(),
+	}
+
+	status.Phase, _, _ = unstructured.NestedString(cluster.Object, "status", "phase")                           //nolint:errcheck
+	status.ControlPlaneReady, _, _ = unstructured.NestedBool(cluster.Object, "status", "controlPlaneReady")     //nolint:errcheck
+	status.InfrastructureReady, _, _ = unstructured.NestedBool(cluster.Object, "status", "infrastructureReady") //nolint:errcheck
+
+	if value, ok := status.Annotations[constants.ExpiresAtAnnotation]; ok {
+		if expiresAt, err := time.Parse(time.RFC3339, value); err == nil {
+			status.ExpiresAt = &expiresAt
+		}
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(cluster.Object, "status", "conditions") //nolint:errcheck
+
+	for _, c := range conditions {
+		condition, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		field := func(key string) string {
+			value, _ := condition[key].(string) //nolint:errcheck
+
+			return value
+		}
+
+		status.Conditions = append(status.Conditions, ClusterCondition{
+			Type:    field("type"),
+			Status:  field("status"),
+			Reason:  field("reason"),
+			Message: field("message"),
+		})
+	}
+
+	return status
+}
@@ -0,0 +1,149 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// OperationStatus is the state of the long running operation.
+type OperationStatus string
+
+// Operation statuses.
+const (
+	OperationRunning   OperationStatus = "running"
+	OperationSucceeded OperationStatus = "succeeded"
+	OperationFailed    OperationStatus = "failed"
+)
+
+// OperationType is the kind of the long running operation.
+type OperationType string
+
+// Operation types.
+const (
+	OperationCreate OperationType = "create"
+	OperationScale  OperationType = "scale"
+	OperationDelete OperationType = "delete"
+	OperationHealth OperationType = "health"
+)
+
+// Operation tracks a long running cluster operation.
+type Operation struct {
+	StartedAt  time.Time       `json:"startedAt"`
+	FinishedAt *time.Time      `json:"finishedAt,omitempty"`
+	ID         string          `json:"id"`
+	Type       OperationType   `json:"type"`
+	Cluster    string          `json:"cluster"`
+	Namespace  string          `json:"namespace"`
+	Status     OperationStatus `json:"status"`
+	Error      string          `json:"error,omitempty"`
+}
+
+// startOperation runs the operation in the background.
+//
+// Only one operation per cluster runs at a time.
+func (s *Server) startOperation(opType OperationType, name, namespace string, run func(ctx context.Context) error) (Operation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purgeOperations()
+
+	for _, op := range s.operations {
+		if op.Status == OperationRunning && op.Cluster == name && op.Namespace == namespace {
+			return Operation{}, &requestError{
+				status: http.StatusConflict,
+				err:    fmt.Errorf("cluster %s/%s has a running %s operation %s", namespace, name, op.Type, op.ID),
+			}
+		}
+	}
+
+	op := &Operation{
+		ID:        rand.String(16),
+		Type:      opType,
+		Cluster:   name,
+		Namespace: namespace,
+		Status:    OperationRunning,
+		StartedAt: time.Now(),
+	}
+
+	s.operations[op.ID] = op
+
+	s.wg.Add(1)
+
+	go func() {
+		defer s.wg.Done()
+
+		err := run(s.ctx)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		finishedAt := time.Now()
+
+		op.FinishedAt = &finishedAt
+		op.Status = OperationSucceeded
+
+		if err != nil {
+			op.Status = OperationFailed
+			op.Error = err.Error()
+		}
+	}()
+
+	return *op, nil
+}
+
+// Wait blocks until all running operations finish.
+func (s *Server) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Server) getOperation(id string) (Operation, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	op, ok := s.operations[id]
+	if !ok {
+		return Operation{}, false
+	}
+
+	return *op, true
+}
+
+func (s *Server) listOperations() []Operation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([]Operation, 0, len(s.operations))
+
+	for _, op := range s.operations {
+		res = append(res, *op)
+	}
+
+	slices.SortFunc(res, func(a, b Operation) int {
+		if c := a.StartedAt.Compare(b.StartedAt); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return res
+}
+
+// purgeOperations forgets the operations finished longer than the retention period ago.
+func (s *Server) purgeOperations() {
+	for id, op := range s.operations {
+		if op.FinishedAt != nil && time.Since(*op.FinishedAt) > s.options.OperationRetention {
+			delete(s.operations, id)
+		}
+	}
+}
@@ -0,0 +1,440 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+// Package server implements HTTP/JSON API for the cluster lifecycle.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/siderolabs/capi-utils/pkg/capi"
+)
+
+// OpenAPI is the OpenAPI 3 spec of the server API.
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
+
+const maxRequestSize = 1 << 20
+
+// Options server options.
+type Options struct {
+	// BearerToken authenticates the API requests, the API is not served if it is empty.
+	BearerToken string
+	// OperationRetention is how long the finished operations are kept.
+	OperationRetention time.Duration
+}
+
+// Option defines a single server option.
+type Option func(opts *Options)
+
+// WithOperationRetention sets how long the finished operations are kept.
+func WithOperationRetention(retention time.Duration) Option {
+	return func(opts *Options) {
+		opts.OperationRetention = retention
+	}
+}
+
+// WithBearerToken sets the token the API requests are authenticated with.
+func WithBearerToken(token string) Option {
+	return func(opts *Options) {
+		opts.BearerToken = token
+	}
+}
+
+// Server exposes the cluster operations as REST API.
+//
+// Create, scale, delete and health requests start long running operations
+// and return them with 202 Accepted, the operation state is polled by ID.
+type Server struct {
+	ctx        context.Context //nolint:containedctx
+	backend    Backend
+	operations map[string]*Operation
+	options    Options
+	wg         sync.WaitGroup
+	mu         sync.Mutex
+}
+
+// New creates the server, ctx bounds the lifetime of the long running operations.
+func New(ctx context.Context, backend Backend, setters ...Option) *Server {
+	options := Options{
+		OperationRetention: time.Hour,
+	}
+
+	for _, setter := range setters {
+		setter(&options)
+	}
+
+	return &Server{
+		ctx:        ctx,
+		backend:    backend,
+		operations: map[string]*Operation{},
+		options:    options,
+	}
+}
+
+// Handler returns the HTTP handler serving the API.
+//
+// The /v1 routes require the bearer token, the OpenAPI spec is served without authentication.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /openapi.yaml", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(OpenAPI) //nolint:errcheck
+	})
+
+	api := http.NewServeMux()
+
+	api.HandleFunc("GET /v1/clusters", s.handle(s.listClusters))
+	api.HandleFunc("POST /v1/clusters", s.handle(s.createCluster))
+	api.HandleFunc("GET /v1/namespaces/{namespace}/clusters/{name}", s.handle(s.getCluster))
+	api.HandleFunc("DELETE /v1/namespaces/{namespace}/clusters/{name}", s.handle(s.deleteCluster))
+	api.HandleFunc("POST /v1/namespaces/{namespace}/clusters/{name}/scale", s.handle(s.scaleCluster))
+	api.HandleFunc("POST /v1/namespaces/{namespace}/clusters/{name}/health", s.handle(s.health))
+	api.HandleFunc("GET /v1/namespaces/{namespace}/clusters/{name}/kubeconfig", s.download(s.backend.Kubeconfig))
+	api.HandleFunc("GET /v1/namespaces/{namespace}/clusters/{name}/talosconfig", s.download(s.backend.Talosconfig))
+	api.HandleFunc("GET /v1/operations", s.handle(s.listOperationsHandler))
+	api.HandleFunc("GET /v1/operations/{id}", s.handle(s.getOperationHandler))
+
+	mux.Handle("/v1/", s.authenticate(api))
+
+	return mux
+}
+
+// authenticate rejects the requests without the configured bearer token.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		if !ok || s.options.BearerToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.options.BearerToken)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="capi-utils"`)
+			writeJSON(w, http.StatusUnauthorized, errorResponse{Error: "missing or invalid bearer token"})
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// response is the handler result encoded as JSON.
+type response struct {
+	body   any
+	status int
+}
+
+// requestError is an error with the HTTP status.
+type requestError struct {
+	err    error
+	status int
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+func badRequest(format string, args ...any) error {
+	return &requestError{
+		status: http.StatusBadRequest,
+		err:    fmt.Errorf(format, args...),
+	}
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handle(h func(r *http.Request) (response, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp, err := h(r)
+		if err != nil {
+			writeJSON(w, errorStatus(err), errorResponse{Error: err.Error()})
+
+			return
+		}
+
+		writeJSON(w, resp.status, resp.body)
+	}
+}
+
+func (s *Server) download(get func(ctx context.Context, name, namespace string) ([]byte, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name, namespace := r.PathValue("name"), r.PathValue("namespace")
+
+		_, err := s.selectedCluster(r, name, namespace)
+		if err != nil {
+			writeJSON(w, errorStatus(err), errorResponse{Error: err.Error()})
+
+			return
+		}
+
+		data, err := get(r.Context(), name, namespace)
+		if err != nil {
+			writeJSON(w, errorStatus(err), errorResponse{Error: err.Error()})
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(data) //nolint:errcheck
+	}
+}
+
+func errorStatus(err error) int {
+	var reqErr *requestError
+
+	switch {
+	case errors.As(err, &reqErr):
+		return reqErr.status
+	case apierrors.IsNotFound(err):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	json.NewEncoder(w).Encode(body) //nolint:errcheck,errchkjson
+}
+
+func decodeBody(r *http.Request, v any) error {
+	decoder := json.NewDecoder(io.LimitReader(r.Body, maxRequestSize))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(v); err != nil {
+		return badRequest("invalid request body: %s", err)
+	}
+
+	return nil
+}
+
+// selector builds the label selector from the selector and all query parameters.
+func selector(r *http.Request) (labels.Selector, error) {
+	query := r.URL.Query()
+
+	extra, err := labels.ConvertSelectorToLabelsMap(query.Get("selector"))
+	if err != nil {
+		return nil, badRequest("invalid selector: %s", err)
+	}
+
+	all, err := queryBool(r, "all")
+	if err != nil {
+		return nil, err
+	}
+
+	if all {
+		return labels.SelectorFromSet(extra), nil
+	}
+
+	return capi.ManagedSelector(extra), nil
+}
+
+func queryBool(r *http.Request, key string) (bool, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return false, nil
+	}
+
+	res, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, badRequest("invalid %s value %q", key, value)
+	}
+
+	return res, nil
+}
+
+func (s *Server) listClusters(r *http.Request) (response, error) {
+	selector, err := selector(r)
+	if err != nil {
+		return response{}, err
+	}
+
+	clusters, err := s.backend.ListClusters(r.Context(), r.URL.Query().Get("namespace"), selector)
+	if err != nil {
+		return response{}, err
+	}
+
+	return response{status: http.StatusOK, body: clusters}, nil
+}
+
+func (s *Server) getCluster(r *http.Request) (response, error) {
+	cluster, err := s.backend.GetCluster(r.Context(), r.PathValue("name"), r.PathValue("namespace"))
+	if err != nil {
+		return response{}, err
+	}
+
+	return response{status: http.StatusOK, body: cluster}, nil
+}
+
+func (s *Server) createCluster(r *http.Request) (response, error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	if err != nil {
+		return response{}, err
+	}
+
+	spec, err := capi.LoadClusterSpec(data)
+	if err != nil {
+		return response{}, badRequest("%s", err)
+	}
+
+	if spec.Name == "" {
+		return response{}, badRequest("cluster spec name is required")
+	}
+
+	if err = checkRemoteSpec(spec); err != nil {
+		return response{}, err
+	}
+
+	if spec.Namespace == "" {
+		spec.Namespace = capi.DefaultDeployOptions().ClusterNamespace
+	}
+
+	return s.accepted(s.startOperation(OperationCreate, spec.Name, spec.Namespace, func(ctx context.Context) error {
+		return s.backend.CreateCluster(ctx, spec)
+	}))
+}
+
+// checkRemoteSpec rejects the spec fields which read files on the server.
+func checkRemoteSpec(spec *capi.ClusterSpec) error {
+	if file := spec.Template.File; file != "" {
+		if err := capi.CheckRemoteTemplateSource(file); err != nil {
+			return badRequest("%s", err)
+		}
+	}
+
+	for _, patch := range slices.Concat(spec.ControlPlane.ConfigPatches, spec.Workers.ConfigPatches) {
+		if strings.HasPrefix(patch, "@") {
+			return badRequest("config patches should be inline, got %q", patch)
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) scaleCluster(r *http.Request) (response, error) {
+	var request ScaleRequest
+
+	if err := decodeBody(r, &request); err != nil {
+		return response{}, err
+	}
+
+	if request.ControlPlaneNodes == nil && request.WorkerNodes == nil {
+		return response{}, badRequest("either controlPlaneNodes or workerNodes should be set")
+	}
+
+	for _, nodes := range []*int{request.ControlPlaneNodes, request.WorkerNodes} {
+		if nodes != nil && *nodes < 0 {
+			return response{}, badRequest("number of nodes should not be negative")
+		}
+	}
+
+	name, namespace := r.PathValue("name"), r.PathValue("namespace")
+
+	if _, err := s.selectedCluster(r, name, namespace); err != nil {
+		return response{}, err
+	}
+
+	return s.accepted(s.startOperation(OperationScale, name, namespace, func(ctx context.Context) error {
+		return s.backend.ScaleCluster(ctx, name, namespace, request)
+	}))
+}
+
+func (s *Server) deleteCluster(r *http.Request) (response, error) {
+	selector, err := selector(r)
+	if err != nil {
+		return response{}, err
+	}
+
+	name, namespace := r.PathValue("name"), r.PathValue("namespace")
+
+	if _, err = s.selectedCluster(r, name, namespace); err != nil {
+		return response{}, err
+	}
+
+	return s.accepted(s.startOperation(OperationDelete, name, namespace, func(ctx context.Context) error {
+		return s.backend.DeleteCluster(ctx, name, namespace, selector)
+	}))
+}
+
+func (s *Server) health(r *http.Request) (response, error) {
+	name, namespace := r.PathValue("name"), r.PathValue("namespace")
+
+	if _, err := s.selectedCluster(r, name, namespace); err != nil {
+		return response{}, err
+	}
+
+	return s.accepted(s.startOperation(OperationHealth, name, namespace, func(ctx context.Context) error {
+		return s.backend.Health(ctx, name, namespace)
+	}))
+}
+
+// selectedCluster gets the cluster and checks it matches the request selector,
+// so only the clusters created by capi-utils are changed or exposed unless all=true is passed.
+func (s *Server) selectedCluster(r *http.Request, name, namespace string) (*ClusterStatus, error) {
+	selector, err := selector(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := s.backend.GetCluster(r.Context(), name, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if !selector.Matches(labels.Set(cluster.Labels)) {
+		return nil, &requestError{
+			status: http.StatusForbidden,
+			err:    fmt.Errorf("cluster %s/%s does not match the selector %q", namespace, name, selector),
+		}
+	}
+
+	return cluster, nil
+}
+
+func (s *Server) accepted(op Operation, err error) (response, error) {
+	if err != nil {
+		return response{}, err
+	}
+
+	return response{status: http.StatusAccepted, body: op}, nil
+}
+
+func (s *Server) listOperationsHandler(*http.Request) (response, error) {
+	return response{status: http.StatusOK, body: s.listOperations()}, nil
+}
+
+func (s *Server) getOperationHandler(r *http.Request) (response, error) {
+	op, ok := s.getOperation(r.PathValue("id"))
+	if !ok {
+		return response{}, &requestError{
+			status: http.StatusNotFound,
+			err:    fmt.Errorf("operation %q not found", r.PathValue("id")),
+		}
+	}
+
+	return response{status: http.StatusOK, body: op}, nil
+}
@@ -0,0 +1,589 @@
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this
+// file, You can obtain one at http://mozilla.org/MPL/2.0/.
+
+package server_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/siderolabs/capi-utils/pkg/capi"
+	capiconstants "github.com/siderolabs/capi-utils/pkg/constants"
+	"github.com/siderolabs/capi-utils/pkg/server"
+)
+
+const testToken = "secret"
+
+// fakeBackend keeps the clusters in memory, the operations block until release is closed if it is set.
+type fakeBackend struct {
+	clusters map[string]*server.ClusterStatus
+	release  chan struct{}
+	created  []*capi.ClusterSpec
+	scaled   []server.ScaleRequest
+	deleted  []string
+	mu       sync.Mutex
+}
+
+func newFakeBackend(clusters ...server.ClusterStatus) *fakeBackend {
+	b := &fakeBackend{
+		clusters: map[string]*server.ClusterStatus{},
+	}
+
+	for _, cluster := range clusters {
+		b.clusters[cluster.Namespace+"/"+cluster.Name] = &cluster
+	}
+
+	return b
+}
+
+func (b *fakeBackend) wait(ctx context.Context) error {
+	if b.release == nil {
+		return nil
+	}
+
+	select {
+	case <-b.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *fakeBackend) get(name, namespace string) (*server.ClusterStatus, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cluster, ok := b.clusters[namespace+"/"+name]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: "cluster.x-k8s.io", Resource: "clusters"}, name)
+	}
+
+	return cluster, nil
+}
+
+func (b *fakeBackend) ListClusters(_ context.Context, namespace string, selector labels.Selector) ([]server.ClusterStatus, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var res []server.ClusterStatus
+
+	for _, cluster := range b.clusters {
+		if (namespace == "" || cluster.Namespace == namespace) && selector.Matches(labels.Set(cluster.Labels)) {
+			res = append(res, *cluster)
+		}
+	}
+
+	return res, nil
+}
+
+func (b *fakeBackend) GetCluster(_ context.Context, name, namespace string) (*server.ClusterStatus, error) {
+	return b.get(name, namespace)
+}
+
+func (b *fakeBackend) CreateCluster(ctx context.Context, spec *capi.ClusterSpec) error {
+	if err := b.wait(ctx); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.created = append(b.created, spec)
+
+	if spec.Name == "broken" {
+		return errors.New("deploy failed")
+	}
+
+	b.clusters[spec.Namespace+"/"+spec.Name] = &server.ClusterStatus{
+		Name:      spec.Name,
+		Namespace: spec.Namespace,
+		Labels:    spec.Labels,
+	}
+
+	return nil
+}
+
+func (b *fakeBackend) ScaleCluster(ctx context.Context, _, _ string, request server.ScaleRequest) error {
+	if err := b.wait(ctx); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.scaled = append(b.scaled, request)
+
+	return nil
+}
+
+func (b *fakeBackend) DeleteCluster(ctx context.Context, name, namespace string, _ labels.Selector) error {
+	if err := b.wait(ctx); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.deleted = append(b.deleted, namespace+"/"+name)
+	delete(b.clusters, namespace+"/"+name)
+
+	return nil
+}
+
+func (b *fakeBackend) Kubeconfig(_ context.Context, name, namespace string) ([]byte, error) {
+	if _, err := b.get(name, namespace); err != nil {
+		return nil, err
+	}
+
+	return []byte("kubeconfig: " + name), nil
+}
+
+func (b *fakeBackend) Talosconfig(_ context.Context, name, namespace string) ([]byte, error) {
+	if _, err := b.get(name, namespace); err != nil {
+		return nil, err
+	}
+
+	return []byte("talosconfig: " + name), nil
+}
+
+func (b *fakeBackend) Health(ctx context.Context, name, namespace string) error {
+	if err := b.wait(ctx); err != nil {
+		return err
+	}
+
+	_, err := b.get(name, namespace)
+
+	return err
+}
+
+func managedCluster(name string) server.ClusterStatus {
+	return server.ClusterStatus{
+		Name:      name,
+		Namespace: "default",
+		Labels:    map[string]string{capiconstants.ManagedByLabel: capiconstants.ManagedByValue},
+	}
+}
+
+func setup(t *testing.T, backend *fakeBackend) *httptest.Server {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	srv := server.New(ctx, backend, server.WithBearerToken(testToken))
+	httpServer := httptest.NewServer(srv.Handler())
+
+	t.Cleanup(func() {
+		httpServer.Close()
+		cancel()
+		srv.Wait()
+	})
+
+	return httpServer
+}
+
+func request(t *testing.T, httpServer *httptest.Server, method, path, body string) (int, []byte) {
+	t.Helper()
+
+	return requestWithToken(t, httpServer, method, path, body, testToken)
+}
+
+func requestWithToken(t *testing.T, httpServer *httptest.Server, method, path, body, token string) (int, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), method, httpServer.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpServer.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.StatusCode, data
+}
+
+func decode[T any](t *testing.T, data []byte) T {
+	t.Helper()
+
+	var res T
+
+	if err := json.Unmarshal(data, &res); err != nil {
+		t.Fatalf("failed to decode %q: %s", data, err)
+	}
+
+	return res
+}
+
+func expectStatus(t *testing.T, expected, actual int, body []byte) {
+	t.Helper()
+
+	if expected != actual {
+		t.Fatalf("expected status %d, got %d: %s", expected, actual, body)
+	}
+}
+
+// waitOperation polls the operation until it is finished.
+func waitOperation(t *testing.T, httpServer *httptest.Server, id string) server.Operation {
+	t.Helper()
+
+	deadline := time.Now().Add(10 * time.Second)
+
+	for time.Now().Before(deadline) {
+		status, body := request(t, httpServer, http.MethodGet, "/v1/operations/"+id, "")
+		expectStatus(t, http.StatusOK, status, body)
+
+		op := decode[server.Operation](t, body)
+		if op.Status != server.OperationRunning {
+			return op
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("operation %s is still running", id)
+
+	return server.Operation{}
+}
+
+const clusterSpec = `{
+	"apiVersion": "capi-utils.siderolabs.dev/v1alpha1",
+	"kind": "ClusterSpec",
+	"name": %q,
+	"labels": {"team": "platform"}
+}`
+
+func spec(name string) string {
+	return fmt.Sprintf(clusterSpec, name)
+}
+
+func TestAuthentication(t *testing.T) {
+	httpServer := setup(t, newFakeBackend())
+
+	for _, tt := range []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{name: "missing", status: http.StatusUnauthorized},
+		{name: "invalid", token: "wrong", status: http.StatusUnauthorized},
+		{name: "valid", token: testToken, status: http.StatusOK},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := requestWithToken(t, httpServer, http.MethodGet, "/v1/clusters", "", tt.token)
+			expectStatus(t, tt.status, status, body)
+		})
+	}
+
+	status, body := requestWithToken(t, httpServer, http.MethodGet, "/openapi.yaml", "", "")
+	expectStatus(t, http.StatusOK, status, body)
+}
+
+func TestAuthenticationWithoutToken(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// the API is not served if the token is not configured
+	httpServer := httptest.NewServer(server.New(ctx, newFakeBackend()).Handler())
+	t.Cleanup(httpServer.Close)
+
+	status, body := requestWithToken(t, httpServer, http.MethodGet, "/v1/clusters", "", "")
+	expectStatus(t, http.StatusUnauthorized, status, body)
+
+	status, body = requestWithToken(t, httpServer, http.MethodGet, "/v1/clusters", "", " ")
+	expectStatus(t, http.StatusUnauthorized, status, body)
+}
+
+func TestCreateCluster(t *testing.T) {
+	backend := newFakeBackend()
+	httpServer := setup(t, backend)
+
+	status, body := request(t, httpServer, http.MethodPost, "/v1/clusters", spec("test"))
+	expectStatus(t, http.StatusAccepted, status, body)
+
+	op := decode[server.Operation](t, body)
+
+	if op.Type != server.OperationCreate || op.Cluster != "test" || op.Namespace != "default" || op.Status != server.OperationRunning {
+		t.Fatalf("unexpected operation %+v", op)
+	}
+
+	if op = waitOperation(t, httpServer, op.ID); op.Status != server.OperationSucceeded || op.FinishedAt == nil {
+		t.Fatalf("unexpected operation %+v", op)
+	}
+
+	if len(backend.created) != 1 || backend.created[0].Labels["team"] != "platform" {
+		t.Fatalf("unexpected created clusters %+v", backend.created)
+	}
+
+	status, body = request(t, httpServer, http.MethodGet, "/v1/namespaces/default/clusters/test", "")
+	expectStatus(t, http.StatusOK, status, body)
+
+	if cluster := decode[server.ClusterStatus](t, body); cluster.Name != "test" {
+		t.Fatalf("unexpected cluster %+v", cluster)
+	}
+
+	status, body = request(t, httpServer, http.MethodGet, "/v1/operations", "")
+	expectStatus(t, http.StatusOK, status, body)
+
+	if ops := decode[[]server.Operation](t, body); len(ops) != 1 || ops[0].ID != op.ID {
+		t.Fatalf("unexpected operations %+v", ops)
+	}
+}
+
+func TestCreateClusterFailed(t *testing.T) {
+	httpServer := setup(t, newFakeBackend())
+
+	status, body := request(t, httpServer, http.MethodPost, "/v1/clusters", spec("broken"))
+	expectStatus(t, http.StatusAccepted, status, body)
+
+	op := waitOperation(t, httpServer, decode[server.Operation](t, body).ID)
+
+	if op.Status != server.OperationFailed || op.Error != "deploy failed" {
+		t.Fatalf("unexpected operation %+v", op)
+	}
+}
+
+func TestCreateClusterBadRequest(t *testing.T) {
+	httpServer := setup(t, newFakeBackend())
+
+	for _, tt := range []struct {
+		name string
+		body string
+	}{
+		{name: "invalid body", body: "{"},
+		{name: "unknown field", body: strings.Replace(spec("test"), `"kind"`, `"unknown": true, "kind"`, 1)},
+		{name: "missing name", body: spec("")},
+		{name: "local template", body: strings.Replace(spec("test"), `"kind"`, `"template": {"file": "/etc/passwd"}, "kind"`, 1)},
+		{name: "file template", body: strings.Replace(spec("test"), `"kind"`, `"template": {"file": "file:///etc/passwd"}, "kind"`, 1)},
+		{name: "git file template", body: strings.Replace(spec("test"), `"kind"`, `"template": {"file": "git+file:///repo//t.yaml"}, "kind"`, 1)},
+		{name: "file config patch", body: strings.Replace(spec("test"), `"kind"`, `"controlPlane": {"configPatches": ["@patch.yaml"]}, "kind"`, 1)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := request(t, httpServer, http.MethodPost, "/v1/clusters", tt.body)
+			expectStatus(t, http.StatusBadRequest, status, body)
+
+			if decode[map[string]string](t, body)["error"] == "" {
+				t.Fatalf("expected error message, got %s", body)
+			}
+		})
+	}
+}
+
+func TestScaleCluster(t *testing.T) {
+	backend := newFakeBackend(managedCluster("test"), server.ClusterStatus{Name: "unmanaged", Namespace: "default"})
+	httpServer := setup(t, backend)
+
+	status, body := request(t, httpServer, http.MethodPost, "/v1/namespaces/default/clusters/test/scale", `{"workerNodes": 3}`)
+	expectStatus(t, http.StatusAccepted, status, body)
+
+	op := decode[server.Operation](t, body)
+
+	if op.Type != server.OperationScale {
+		t.Fatalf("unexpected operation %+v", op)
+	}
+
+	if op = waitOperation(t, httpServer, op.ID); op.Status != server.OperationSucceeded {
+		t.Fatalf("unexpected operation %+v", op)
+	}
+
+	if len(backend.scaled) != 1 || backend.scaled[0].ControlPlaneNodes != nil || *backend.scaled[0].WorkerNodes != 3 {
+		t.Fatalf("unexpected scale requests %+v", backend.scaled)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		path   string
+		body   string
+		status int
+	}{
+		{name: "empty", path: "default/clusters/test", body: `{}`, status: http.StatusBadRequest},
+		{name: "negative", path: "default/clusters/test", body: `{"controlPlaneNodes": -1}`, status: http.StatusBadRequest},
+		{name: "unknown field", path: "default/clusters/test", body: `{"nodes": 1}`, status: http.StatusBadRequest},
+		{name: "not found", path: "default/clusters/missing", body: `{"workerNodes": 1}`, status: http.StatusNotFound},
+		{name: "unmanaged", path: "default/clusters/unmanaged", body: `{"workerNodes": 0}`, status: http.StatusForbidden},
+		{name: "selector mismatch", path: "default/clusters/test?selector=team=platform", body: `{"workerNodes": 0}`, status: http.StatusForbidden},
+		{name: "unmanaged with all", path: "default/clusters/unmanaged?all=true", body: `{"workerNodes": 0}`, status: http.StatusAccepted},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			path, query, _ := strings.Cut(tt.path, "?")
+
+			status, body := request(t, httpServer, http.MethodPost, "/v1/namespaces/"+path+"/scale?"+query, tt.body)
+			expectStatus(t, tt.status, status, body)
+		})
+	}
+}
+
+func TestDeleteCluster(t *testing.T) {
+	unmanaged := server.ClusterStatus{Name: "unmanaged", Namespace: "default"}
+
+	backend := newFakeBackend(managedCluster("test"), unmanaged)
+	httpServer := setup(t, backend)
+
+	status, body := request(t, httpServer, http.MethodDelete, "/v1/namespaces/default/clusters/unmanaged", "")
+	expectStatus(t, http.StatusForbidden, status, body)
+
+	status, body = request(t, httpServer, http.MethodDelete, "/v1/namespaces/default/clusters/test?selector=team=platform", "")
+	expectStatus(t, http.StatusForbidden, status, body)
+
+	status, body = request(t, httpServer, http.MethodDelete, "/v1/namespaces/default/clusters/test?all=maybe", "")
+	expectStatus(t, http.StatusBadRequest, status, body)
+
+	status, body = request(t, httpServer, http.MethodDelete, "/v1/namespaces/default/clusters/missing", "")
+	expectStatus(t, http.StatusNotFound, status, body)
+
+	status, body = request(t, httpServer, http.MethodDelete, "/v1/namespaces/default/clusters/test", "")
+	expectStatus(t, http.StatusAccepted, status, body)
+
+	if op := waitOperation(t, httpServer, decode[server.Operation](t, body).ID); op.Type != server.OperationDelete || op.Status != server.OperationSucceeded {
+		t.Fatalf("unexpected operation %+v", op)
+	}
+
+	if len(backend.deleted) != 1 || backend.deleted[0] != "default/test" {
+		t.Fatalf("unexpected deleted clusters %v", backend.deleted)
+	}
+
+	status, body = request(t, httpServer, http.MethodGet, "/v1/namespaces/default/clusters/test", "")
+	expectStatus(t, http.StatusNotFound, status, body)
+}
+
+func TestDownload(t *testing.T) {
+	httpServer := setup(t, newFakeBackend(managedCluster("test"), server.ClusterStatus{Name: "unmanaged", Namespace: "default"}))
+
+	for _, config := range []string{"kubeconfig", "talosconfig"} {
+		t.Run(config, func(t *testing.T) {
+			status, body := request(t, httpServer, http.MethodGet, "/v1/namespaces/default/clusters/test/"+config, "")
+			expectStatus(t, http.StatusOK, status, body)
+
+			if string(body) != config+": test" {
+				t.Fatalf("unexpected %s %q", config, body)
+			}
+
+			status, body = request(t, httpServer, http.MethodGet, "/v1/namespaces/default/clusters/missing/"+config, "")
+			expectStatus(t, http.StatusNotFound, status, body)
+
+			// credentials of the clusters not created by capi-utils are not exposed by default
+			status, body = request(t, httpServer, http.MethodGet, "/v1/namespaces/default/clusters/unmanaged/"+config, "")
+			expectStatus(t, http.StatusForbidden, status, body)
+
+			status, body = request(t, httpServer, http.MethodGet, "/v1/namespaces/default/clusters/unmanaged/"+config+"?all=true", "")
+			expectStatus(t, http.StatusOK, status, body)
+		})
+	}
+}
+
+func TestOperationConflict(t *testing.T) {
+	backend := newFakeBackend(managedCluster("test"), managedCluster("other"))
+	backend.release = make(chan struct{})
+
+	httpServer := setup(t, backend)
+
+	status, body := request(t, httpServer, http.MethodPost, "/v1/namespaces/default/clusters/test/scale", `{"workerNodes": 2}`)
+	expectStatus(t, http.StatusAccepted, status, body)
+
+	running := decode[server.Operation](t, body)
+
+	// only one operation per cluster runs at a time
+	status, body = request(t, httpServer, http.MethodPost, "/v1/namespaces/default/clusters/test/health", "")
+	expectStatus(t, http.StatusConflict, status, body)
+
+	status, body = request(t, httpServer, http.MethodDelete, "/v1/namespaces/default/clusters/test", "")
+	expectStatus(t, http.StatusConflict, status, body)
+
+	// other clusters are not blocked
+	status, body = request(t, httpServer, http.MethodPost, "/v1/namespaces/default/clusters/other/health", "")
+	expectStatus(t, http.StatusAccepted, status, body)
+
+	other := decode[server.Operation](t, body)
+
+	close(backend.release)
+
+	for _, id := range []string{running.ID, other.ID} {
+		if op := waitOperation(t, httpServer, id); op.Status != server.OperationSucceeded {
+			t.Fatalf("unexpected operation %+v", op)
+		}
+	}
+
+	status, body = request(t, httpServer, http.MethodPost, "/v1/namespaces/default/clusters/test/health", "")
+	expectStatus(t, http.StatusAccepted, status, body)
+}
+
+func TestOperationNotFound(t *testing.T) {
+	httpServer := setup(t, newFakeBackend())
+
+	status, body := request(t, httpServer, http.MethodGet, "/v1/operations/missing", "")
+	expectStatus(t, http.StatusNotFound, status, body)
+}
+
+func TestListClusters(t *testing.T) {
+	team := managedCluster("team")
+	team.Labels["team"] = "platform"
+
+	httpServer := setup(t, newFakeBackend(managedCluster("test"), team, server.ClusterStatus{Name: "unmanaged", Namespace: "default"}))
+
+	for _, tt := range []struct {
+		query    string
+		expected int
+		status   int
+	}{
+		{query: "", expected: 2, status: http.StatusOK},
+		{query: "?selector=team=platform", expected: 1, status: http.StatusOK},
+		{query: "?all=true", expected: 3, status: http.StatusOK},
+		{query: "?namespace=other", expected: 0, status: http.StatusOK},
+		{query: "?selector=team", status: http.StatusBadRequest},
+	} {
+		t.Run(tt.query, func(t *testing.T) {
+			status, body := request(t, httpServer, http.MethodGet, "/v1/clusters"+tt.query, "")
+			expectStatus(t, tt.status, status, body)
+
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			if clusters := decode[[]server.ClusterStatus](t, body); len(clusters) != tt.expected {
+				t.Fatalf("expected %d clusters, got %+v", tt.expected, clusters)
+			}
+		})
+	}
+}
+
+func TestHealth(t *testing.T) {
+	httpServer := setup(t, newFakeBackend(managedCluster("test"), server.ClusterStatus{Name: "unmanaged", Namespace: "default"}))
+
+	status, body := request(t, httpServer, http.MethodPost, "/v1/namespaces/default/clusters/unmanaged/health", "")
+	expectStatus(t, http.StatusForbidden, status, body)
+
+	status, body = request(t, httpServer, http.MethodPost, "/v1/namespaces/default/clusters/missing/health", "")
+	expectStatus(t, http.StatusNotFound, status, body)
+
+	status, body = request(t, httpServer, http.MethodPost, "/v1/namespaces/default/clusters/test/health", "")
+	expectStatus(t, http.StatusAccepted, status, body)
+
+	if op := waitOperation(t, httpServer, decode[server.Operation](t, body).ID); op.Type != server.OperationHealth || op.Status != server.OperationSucceeded {
+		t.Fatalf("unexpected operation %+v", op)
+	}
+}